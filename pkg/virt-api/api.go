@@ -88,6 +88,8 @@ const (
 	defaultTlsKeyFilePath      = "/etc/virt-api/certificates/tls.key"
 	defaultHandlerCertFilePath = "/etc/virt-handler/clientcertificates/tls.crt"
 	defaultHandlerKeyFilePath  = "/etc/virt-handler/clientcertificates/tls.key"
+	defaultMetricsCertFilePath = "/etc/virt-api/metrics-certificates/tls.crt"
+	defaultMetricsKeyFilePath  = "/etc/virt-api/metrics-certificates/tls.key"
 
 	httpStatusNotFoundMessage     = "Not Found"
 	httpStatusBadRequestMessage   = "Bad Request"
@@ -120,12 +122,17 @@ type virtAPIApp struct {
 	certmanager             certificate2.Manager
 	handlerTLSConfiguration *tls.Config
 	handlerCertManager      certificate2.Manager
+	metricsTLSConfig        *tls.Config
+	metricsCertManager      certificate2.Manager
 
 	caConfigMapName              string
 	tlsCertFilePath              string
 	tlsKeyFilePath               string
 	handlerCertFilePath          string
 	handlerKeyFilePath           string
+	metricsPort                  int
+	metricsCertFilePath          string
+	metricsKeyFilePath           string
 	externallyManaged            bool
 	reloadableRateLimiter        *ratelimiter.ReloadableRateLimiter
 	reloadableWebhookRateLimiter *ratelimiter.ReloadableRateLimiter
@@ -805,6 +812,9 @@ func (app *virtAPIApp) readRequestHeader() error {
 func (app *virtAPIApp) prepareCertManager() {
 	app.certmanager = bootstrap.NewFileCertificateManager(app.tlsCertFilePath, app.tlsKeyFilePath)
 	app.handlerCertManager = bootstrap.NewFileCertificateManager(app.handlerCertFilePath, app.handlerKeyFilePath)
+	if app.metricsPort != 0 {
+		app.metricsCertManager = bootstrap.NewFileCertificateManager(app.metricsCertFilePath, app.metricsKeyFilePath)
+	}
 }
 
 func (app *virtAPIApp) registerValidatingWebhooks(informers *webhooks.Informers) {
@@ -903,6 +913,9 @@ func (app *virtAPIApp) setupTLS(k8sCAManager kvtls.ClientCAManager, kubevirtCAMa
 	// and our aggregated endpoint never becomes available.
 	app.tlsConfig = kvtls.SetupTLSWithCertManager(k8sCAManager, app.certmanager, tls.VerifyClientCertIfGiven, app.clusterConfig)
 	app.handlerTLSConfiguration = kvtls.SetupTLSForVirtHandlerClients(kubevirtCAManager, app.handlerCertManager, app.externallyManaged)
+	if app.metricsCertManager != nil {
+		app.metricsTLSConfig = kvtls.SetupTLSWithCertManager(k8sCAManager, app.metricsCertManager, tls.VerifyClientCertIfGiven, app.clusterConfig)
+	}
 }
 
 func (app *virtAPIApp) startTLS(informerFactory controller.KubeInformerFactory) error {
@@ -937,6 +950,21 @@ func (app *virtAPIApp) startTLS(informerFactory controller.KubeInformerFactory)
 		errors <- server.ListenAndServeTLS("", "")
 	}()
 
+	// start the dedicated metrics TLS server, if requested
+	var metricsServer *http.Server
+	if app.metricsTLSConfig != nil {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.Handler())
+		metricsServer = &http.Server{
+			Addr:      fmt.Sprintf("%s:%d", app.BindAddress, app.metricsPort),
+			Handler:   mux,
+			TLSConfig: app.metricsTLSConfig,
+		}
+		go func() {
+			errors <- metricsServer.ListenAndServeTLS("", "")
+		}()
+	}
+
 	// start graceful shutdown handler
 	go func() {
 		select {
@@ -967,6 +995,10 @@ func (app *virtAPIApp) startTLS(informerFactory controller.KubeInformerFactory)
 		// Shutdown forces any existing connections that persist after the shutdown
 		// times out to be forced closed.
 		server.Close()
+		if metricsServer != nil {
+			metricsServer.Shutdown(ctx)
+			metricsServer.Close()
+		}
 	}()
 
 	// wait for server to exit
@@ -1055,6 +1087,9 @@ func (app *virtAPIApp) Run() {
 
 	go app.certmanager.Start()
 	go app.handlerCertManager.Start()
+	if app.metricsCertManager != nil {
+		go app.metricsCertManager.Start()
+	}
 
 	// start TLS server
 	// tls server will only accept connections when fetching a certificate and internal configuration passed once
@@ -1120,6 +1155,12 @@ func (app *virtAPIApp) AddFlags() {
 		"Client certificate used to prove the identity of the virt-api when it must call virt-handler during a request")
 	flag.StringVar(&app.handlerKeyFilePath, "handler-key-file", defaultHandlerKeyFilePath,
 		"Private key for the client certificate used to prove the identity of the virt-api when it must call virt-handler during a request")
+	flag.IntVar(&app.metricsPort, "metrics-port", 0,
+		"Port to serve metrics on with a dedicated certificate, 0 serves metrics on the API port")
+	flag.StringVar(&app.metricsCertFilePath, "metrics-tls-cert-file", defaultMetricsCertFilePath,
+		"File containing the x509 Certificate for the metrics port")
+	flag.StringVar(&app.metricsKeyFilePath, "metrics-tls-key-file", defaultMetricsKeyFilePath,
+		"File containing the x509 private key matching --metrics-tls-cert-file")
 	flag.BoolVar(&app.externallyManaged, "externally-managed", false,
 		"Allow intermediate certificates to be used in building up the chain of trust when certificates are externally managed")
 }
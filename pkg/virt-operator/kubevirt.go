@@ -921,7 +921,12 @@ func (c *KubeVirtController) syncInstallation(kv *v1.KubeVirt) error {
 	logger := log.Log.Object(kv)
 	logger.Infof("Handling deployment")
 
-	config := operatorutil.GetTargetConfigFromKV(kv)
+	config, err := operatorutil.ResolveConfig(kv)
+	if err != nil {
+		util.UpdateConditionsFailedError(kv, err)
+		logger.Errorf("Invalid deployment config: %v", err)
+		return err
+	}
 
 	// Record current operator version to status section
 	util.SetOperatorVersion(kv)
@@ -962,7 +967,30 @@ func (c *KubeVirtController) syncInstallation(kv *v1.KubeVirt) error {
 		return err
 	}
 
-	reconciler, err := apply.NewReconciler(kv, targetStrategy, c.stores, c.clientset, c.aggregatorClient, &c.kubeVirtExpectations, c.recorder)
+	kvKey, err := controller.KeyFunc(kv)
+	if err != nil {
+		return err
+	}
+
+	resyncInterval, err := config.GetResyncInterval()
+	if err != nil {
+		util.UpdateConditionsFailedError(kv, err)
+		logger.Errorf("Failed to create reconciler: %v", err)
+		return err
+	}
+
+	opts := []apply.ReconcilerOption{apply.WithResyncInterval(resyncInterval)}
+	if config.PatchEventsEnabled() {
+		opts = append(opts, apply.WithPatchEvents())
+	}
+	if mode := config.GetReconcileMode(); mode != "" {
+		opts = append(opts, apply.WithReconcileMode(apply.ReconcileMode(mode)))
+	}
+	if finalizer := config.GetObjectFinalizer(); finalizer != "" {
+		opts = append(opts, apply.WithObjectFinalizer(finalizer))
+	}
+
+	reconciler, err := apply.NewReconciler(kv, targetStrategy, c.stores, c.clientset, c.aggregatorClient, &c.kubeVirtExpectations, c.recorder, opts...)
 	if err != nil {
 		// deployment failed
 		util.UpdateConditionsFailedError(kv, err)
@@ -979,6 +1007,11 @@ func (c *KubeVirtController) syncInstallation(kv *v1.KubeVirt) error {
 		return err
 	}
 
+	// events are always reconciled right away, the resync interval only forces an additional full reconcile
+	if interval := reconciler.ResyncInterval(); interval != 0 {
+		c.queue.AddAfter(kvKey, interval)
+	}
+
 	// the entire sync can't always occur within a single control loop execution.
 	// when synced==true that means SyncAll() has completed and has nothing left to wait on.
 	if synced {
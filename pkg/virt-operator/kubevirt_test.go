@@ -1234,7 +1234,7 @@ func (k *KubeVirtTestData) addAllWithExclusionMap(config *util.KubeVirtDeploymen
 	all = append(all, components.NewPrometheusRuleCR(config.GetNamespace(), config.WorkloadUpdatesEnabled()))
 	// sccs
 	all = append(all, components.NewKubeVirtControllerSCC(NAMESPACE))
-	all = append(all, components.NewKubeVirtHandlerSCC(NAMESPACE))
+	all = append(all, components.NewKubeVirtHandlerSCC(NAMESPACE, config.HandlerHostNetworkEnabled()))
 	// services and deployments
 	all = append(all, components.NewOperatorWebhookService(NAMESPACE))
 	all = append(all, components.NewPrometheusService(NAMESPACE))
@@ -1954,6 +1954,53 @@ var _ = Describe("KubeVirt Operator", func() {
 
 		})
 
+		It("should reconcile events right away and requeue the forced full reconcile with a resync interval", func() {
+			kvTestData := KubeVirtTestData{}
+			kvTestData.BeforeTest()
+			defer kvTestData.AfterTest()
+
+			kv := &v1.KubeVirt{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-install",
+					Namespace:  NAMESPACE,
+					Finalizers: []string{util.KubeVirtFinalizer},
+					Generation: int64(1),
+					Annotations: map[string]string{
+						util.DeploymentConfigAnnotationPrefix + util.AdditionalPropertiesResyncInterval: "10m",
+					},
+				},
+				Status: v1.KubeVirtStatus{
+					Phase:           v1.KubeVirtPhaseDeployed,
+					OperatorVersion: version.Get().String(),
+				},
+			}
+			kvTestData.defaultConfig = util.GetTargetConfigFromKVWithEnvVarManager(kv, kvTestData.mockEnvVarManager)
+			kvTestData.defaultConfig.SetTargetDeploymentConfig(kv)
+			kvTestData.defaultConfig.SetObservedDeploymentConfig(kv)
+			util.UpdateConditionsCreated(kv)
+			util.UpdateConditionsAvailable(kv)
+
+			// the metrics service went missing after the installation completed
+			kubecontroller.SetLatestApiVersionAnnotation(kv)
+			kvTestData.addKubeVirt(kv)
+			kvTestData.addInstallStrategy(kvTestData.defaultConfig)
+			kvTestData.addAllWithExclusionMap(kvTestData.defaultConfig, kv, map[string]bool{components.PrometheusServiceName: true})
+			kvTestData.addPodsAndPodDisruptionBudgets(kvTestData.defaultConfig, kv)
+			kvTestData.makeDeploymentsReady(kv)
+			kvTestData.makeHandlerReady()
+
+			kvTestData.fakeNamespaceModificationEvent()
+			kvTestData.shouldExpectNamespacePatch()
+			kvTestData.shouldExpectCreations()
+			kvTestData.shouldExpectPatchesAndUpdates(kv)
+			kvTestData.shouldExpectKubeVirtUpdateStatus(1)
+
+			kvTestData.controller.Execute()
+
+			Expect(kvTestData.totalAdds).To(Equal(1))
+			Expect(kvTestData.mockQueue.GetAddAfterEnqueueCount()).To(BeNumerically(">=", 1))
+		})
+
 		It("should update KubeVirt object if generation IDs do not match", func() {
 			kvTestData := KubeVirtTestData{}
 			kvTestData.BeforeTest()
@@ -2103,6 +2150,47 @@ var _ = Describe("KubeVirt Operator", func() {
 
 		})
 
+		It("should fail the deployment with an invalid deployment config", func() {
+			kvTestData := KubeVirtTestData{}
+			kvTestData.BeforeTest()
+			defer kvTestData.AfterTest()
+
+			kv := &v1.KubeVirt{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-install",
+					Namespace: NAMESPACE,
+					Annotations: map[string]string{
+						util.DeploymentConfigAnnotationPrefix + util.AdditionalPropertiesProductionMode: "true",
+					},
+				},
+				Spec: v1.KubeVirtSpec{
+					ImageTag: "latest",
+				},
+			}
+			kubecontroller.SetLatestApiVersionAnnotation(kv)
+			kvTestData.addKubeVirt(kv)
+
+			update := kvTestData.kvInterface.EXPECT().UpdateStatus(gomock.Any())
+			update.Do(func(kv *v1.KubeVirt) {
+				var degraded *v1.KubeVirtCondition
+				for i, condition := range kv.Status.Conditions {
+					if condition.Type == v1.KubeVirtConditionDegraded {
+						degraded = &kv.Status.Conditions[i]
+					}
+				}
+				Expect(degraded).ToNot(BeNil())
+				Expect(degraded.Status).To(Equal(k8sv1.ConditionTrue))
+				Expect(degraded.Reason).To(Equal(util.ConditionReasonDeploymentFailedError))
+				Expect(degraded.Message).To(ContainSubstring("image tag \"latest\" is not allowed in production mode"))
+				kvTestData.kvInformer.GetStore().Update(kv)
+				update.Return(kv, nil)
+			}).Times(1)
+
+			err := kvTestData.controller.execute(fmt.Sprintf("%s/%s", kv.Namespace, kv.Name))
+			Expect(err).To(HaveOccurred())
+			Expect(kvTestData.totalAdds).To(BeZero())
+		})
+
 		It("should generate install strategy creation job for update version", func() {
 			kvTestData := KubeVirtTestData{}
 			kvTestData.BeforeTest()
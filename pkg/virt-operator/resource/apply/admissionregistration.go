@@ -211,7 +211,7 @@ func (r *Reconciler) createOrUpdateValidatingWebhookConfiguration(webhook *admis
 	existingCopy := cachedWebhook.DeepCopy()
 	expectedGeneration := GetExpectedGeneration(webhook, r.kv.Status.Generations)
 
-	resourcemerge.EnsureObjectMeta(modified, &existingCopy.ObjectMeta, webhook.ObjectMeta)
+	ensureObjectMeta(modified, &existingCopy.ObjectMeta, webhook.ObjectMeta)
 	// there was no change to metadata, the generation was right
 	if !*modified && existingCopy.ObjectMeta.Generation == expectedGeneration && certsMatch {
 		log.Log.V(4).Infof("validatingwebhookconfiguration %v is up-to-date", webhook.GetName())
@@ -243,6 +243,7 @@ func (r *Reconciler) createOrUpdateValidatingWebhookConfiguration(webhook *admis
 	if err != nil {
 		return fmt.Errorf("unable to update validatingwebhookconfiguration %+v: %v", webhook, err)
 	}
+	r.recordPatch("ValidatingWebhookConfiguration", "", webhook.Name, generatePatchBytes(ops))
 
 	SetGeneration(&r.kv.Status.Generations, webhook)
 	log.Log.V(2).Infof("validatingwebhoookconfiguration %v updated", webhook.Name)
@@ -316,7 +317,7 @@ func (r *Reconciler) createOrUpdateMutatingWebhookConfiguration(webhook *admissi
 	existingCopy := cachedWebhook.DeepCopy()
 	expectedGeneration := GetExpectedGeneration(webhook, r.kv.Status.Generations)
 
-	resourcemerge.EnsureObjectMeta(modified, &existingCopy.ObjectMeta, webhook.ObjectMeta)
+	ensureObjectMeta(modified, &existingCopy.ObjectMeta, webhook.ObjectMeta)
 	// there was no change to metadata, the generation was right
 	if !*modified && existingCopy.ObjectMeta.Generation == expectedGeneration && certsMatch {
 		log.Log.V(4).Infof("mutating webhook configuration %v is up-to-date", webhook.GetName())
@@ -347,6 +348,7 @@ func (r *Reconciler) createOrUpdateMutatingWebhookConfiguration(webhook *admissi
 	if err != nil {
 		return fmt.Errorf("unable to update mutatingwebhookconfiguration %+v: %v", webhook, err)
 	}
+	r.recordPatch("MutatingWebhookConfiguration", "", webhook.Name, generatePatchBytes(ops))
 
 	SetGeneration(&r.kv.Status.Generations, webhook)
 	log.Log.V(2).Infof("mutatingwebhoookconfiguration %v updated", webhook.Name)
@@ -90,7 +90,7 @@ func (r *Reconciler) syncDeployment(origDeployment *appsv1.Deployment) (*appsv1.
 	existingCopy := cachedDeployment.DeepCopy()
 	expectedGeneration := GetExpectedGeneration(deployment, kv.Status.Generations)
 
-	resourcemerge.EnsureObjectMeta(modified, &existingCopy.ObjectMeta, deployment.ObjectMeta)
+	ensureObjectMeta(modified, &existingCopy.ObjectMeta, deployment.ObjectMeta)
 
 	// there was no change to metadata, the generation matched
 	if !*modified &&
@@ -100,6 +100,15 @@ func (r *Reconciler) syncDeployment(origDeployment *appsv1.Deployment) (*appsv1.
 		return deployment, nil
 	}
 
+	if r.reconcileMode == ReconcileModeReplace {
+		// recreated from the install strategy on the next reconcile
+		if err := r.deleteDeployment(cachedDeployment); err != nil {
+			return nil, err
+		}
+		log.Log.V(2).Infof("deployment %v deleted to be replaced", deployment.GetName())
+		return deployment, nil
+	}
+
 	newSpec, err := json.Marshal(deployment.Spec)
 	if err != nil {
 		return nil, err
@@ -116,6 +125,7 @@ func (r *Reconciler) syncDeployment(origDeployment *appsv1.Deployment) (*appsv1.
 	if err != nil {
 		return nil, fmt.Errorf("unable to update deployment %+v: %v", deployment, err)
 	}
+	r.recordPatch("Deployment", deployment.Namespace, deployment.Name, generatePatchBytes(ops))
 
 	SetGeneration(&kv.Status.Generations, deployment)
 	log.Log.V(2).Infof("deployment %v updated", deployment.GetName())
@@ -159,6 +169,7 @@ func (r *Reconciler) patchDaemonSet(oldDs, newDs *appsv1.DaemonSet) (*appsv1.Dae
 	if err != nil {
 		return nil, fmt.Errorf("unable to update daemonset %+v: %v", oldDs, err)
 	}
+	r.recordPatch("DaemonSet", oldDs.Namespace, oldDs.Name, patch)
 	return newDs, nil
 }
 
@@ -190,6 +201,38 @@ func (r *Reconciler) howManyUpdatedAndReadyPods(daemonSet *appsv1.DaemonSet) int
 	return updatedReadyPods
 }
 
+// hasRollingUpdateParameters returns whether the DaemonSet requests a maxUnavailable or a maxSurge
+func hasRollingUpdateParameters(daemonSet *appsv1.DaemonSet) bool {
+	update := daemonSet.Spec.UpdateStrategy.RollingUpdate
+	return update != nil && (update.MaxUnavailable != nil || update.MaxSurge != nil)
+}
+
+// rolloutRollingUpdate returns the rolling update parameters of the rollout after a successful canary, the ones
+// requested by the target DaemonSet if it sets any
+func rolloutRollingUpdate(daemonSet *appsv1.DaemonSet) *appsv1.RollingUpdateDaemonSet {
+	if hasRollingUpdateParameters(daemonSet) {
+		return daemonSet.Spec.UpdateStrategy.RollingUpdate.DeepCopy()
+	}
+	maxUnavailable := daemonSetFastMaxUnavailable
+	return &appsv1.RollingUpdateDaemonSet{MaxUnavailable: &maxUnavailable}
+}
+
+// targetRollingUpdate returns the rolling update parameters the DaemonSet keeps once the rollout is done, the ones
+// requested by the target DaemonSet if it sets any
+func targetRollingUpdate(daemonSet *appsv1.DaemonSet) *appsv1.RollingUpdateDaemonSet {
+	if hasRollingUpdateParameters(daemonSet) {
+		return daemonSet.Spec.UpdateStrategy.RollingUpdate.DeepCopy()
+	}
+	maxUnavailable := daemonSetDefaultMaxUnavailable
+	return &appsv1.RollingUpdateDaemonSet{MaxUnavailable: &maxUnavailable}
+}
+
+// isCanaryRollingUpdate returns whether the parameters are the ones of the canary, one unavailable pod and no surge
+func isCanaryRollingUpdate(update *appsv1.RollingUpdateDaemonSet) bool {
+	return update.MaxUnavailable != nil && *update.MaxUnavailable == daemonSetDefaultMaxUnavailable &&
+		(update.MaxSurge == nil || update.MaxSurge.IntValue() == 0)
+}
+
 func daemonHasDefaultRolloutStrategy(daemonSet *appsv1.DaemonSet) bool {
 	return getMaxUnavailable(daemonSet) == daemonSetDefaultMaxUnavailable.IntValue()
 }
@@ -226,9 +269,10 @@ func (r *Reconciler) processCanaryUpgrade(cachedDaemonSet, newDS *appsv1.DaemonS
 		}
 		done, status = false, CanaryUpgradeStatusStarted
 	case updatedAndReadyPods > 0 && updatedAndReadyPods < desiredReadyPods:
-		if daemonHasDefaultRolloutStrategy(cachedDaemonSet) {
+		// a rollout with the maxUnavailable of the canary is already running
+		if rollout := rolloutRollingUpdate(newDS); daemonHasDefaultRolloutStrategy(cachedDaemonSet) && !isCanaryRollingUpdate(rollout) {
 			// canary was ok, start real rollout
-			setMaxUnavailable(newDS, daemonSetFastMaxUnavailable)
+			newDS.Spec.UpdateStrategy.RollingUpdate = rollout
 			// start rollout again
 			_, err := r.patchDaemonSet(cachedDaemonSet, newDS)
 			if err != nil {
@@ -243,8 +287,8 @@ func (r *Reconciler) processCanaryUpgrade(cachedDaemonSet, newDS *appsv1.DaemonS
 		done = false
 	case updatedAndReadyPods > 0 && updatedAndReadyPods == desiredReadyPods:
 		// rollout has completed and all virt-handlers are ready
-		// revert maxUnavailable to default value
-		setMaxUnavailable(newDS, daemonSetDefaultMaxUnavailable)
+		// revert the rolling update parameters to the ones of the target
+		newDS.Spec.UpdateStrategy.RollingUpdate = targetRollingUpdate(newDS)
 		newDS, err := r.patchDaemonSet(cachedDaemonSet, newDS)
 		if err != nil {
 			return false, err, CanaryUpgradeStatusFailed
@@ -304,13 +348,33 @@ func (r *Reconciler) syncDaemonSet(daemonSet *appsv1.DaemonSet) (bool, error) {
 	existingCopy := cachedDaemonSet.DeepCopy()
 	expectedGeneration := GetExpectedGeneration(daemonSet, kv.Status.Generations)
 
-	resourcemerge.EnsureObjectMeta(modified, &existingCopy.ObjectMeta, daemonSet.ObjectMeta)
+	ensureObjectMeta(modified, &existingCopy.ObjectMeta, daemonSet.ObjectMeta)
 	// there was no change to metadata, the generation was right
 	if !*modified && existingCopy.GetGeneration() == expectedGeneration {
 		log.Log.V(4).Infof("daemonset %v is up-to-date", daemonSet.GetName())
 		return true, nil
 	}
 
+	if r.reconcileMode == ReconcileModeReplace {
+		// recreated from the install strategy on the next reconcile
+		if err := r.deleteDaemonSet(cachedDaemonSet); err != nil {
+			return false, err
+		}
+		log.Log.V(2).Infof("daemonset %v deleted to be replaced", daemonSet.GetName())
+		return false, nil
+	}
+
+	if daemonSet.Spec.UpdateStrategy.Type == appsv1.OnDeleteDaemonSetStrategyType {
+		// pods only get replaced once they are deleted manually, there is no rollout to drive
+		newDS, err := r.patchDaemonSet(cachedDaemonSet, daemonSet)
+		if err != nil {
+			return false, err
+		}
+		SetGeneration(&kv.Status.Generations, newDS)
+		log.Log.V(2).Infof("daemonSet %v updated, pods are replaced on delete", newDS.GetName())
+		return true, nil
+	}
+
 	// canary pod upgrade
 	// first update virt-handler with maxUnavailable=1
 	// patch daemonSet with new version
@@ -318,7 +382,7 @@ func (r *Reconciler) syncDaemonSet(daemonSet *appsv1.DaemonSet) (bool, error) {
 	// set maxUnavailable=10%
 	// start the rollout of the new virt-handler again
 	// wait for all nodes to complete the rollout
-	// set maxUnavailable back to 1
+	// set the rolling update parameters back to the ones of the target
 	done, err, _ := r.processCanaryUpgrade(cachedDaemonSet, daemonSet, *modified)
 	return done, err
 }
@@ -371,7 +435,7 @@ func (r *Reconciler) syncPodDisruptionBudgetForDeployment(deployment *appsv1.Dep
 	existingCopy := cachedPodDisruptionBudget.DeepCopy()
 	expectedGeneration := GetExpectedGeneration(podDisruptionBudget, kv.Status.Generations)
 
-	resourcemerge.EnsureObjectMeta(modified, &existingCopy.ObjectMeta, podDisruptionBudget.ObjectMeta)
+	ensureObjectMeta(modified, &existingCopy.ObjectMeta, podDisruptionBudget.ObjectMeta)
 	// there was no change to metadata or minAvailable, the generation was right
 	if !*modified &&
 		existingCopy.Spec.MinAvailable.IntValue() == podDisruptionBudget.Spec.MinAvailable.IntValue() &&
@@ -395,6 +459,7 @@ func (r *Reconciler) syncPodDisruptionBudgetForDeployment(deployment *appsv1.Dep
 	if err != nil {
 		return fmt.Errorf("unable to patch/delete poddisruptionbudget %+v: %v", podDisruptionBudget, err)
 	}
+	r.recordPatch("PodDisruptionBudget", podDisruptionBudget.Namespace, podDisruptionBudget.Name, generatePatchBytes(ops))
 
 	SetGeneration(&kv.Status.Generations, podDisruptionBudget)
 	log.Log.V(2).Infof("poddisruptionbudget %v patched", podDisruptionBudget.GetName())
@@ -488,6 +488,50 @@ var _ = Describe("Apply Apps", func() {
 				Expect(done).To(BeFalse())
 			})
 
+			It("should patch without a canary upgrade if virt-handler is updated on delete", func() {
+				mockDSCacheStore.get = daemonSet
+				SetGeneration(&kv.Status.Generations, daemonSet)
+				patched := false
+
+				r := &Reconciler{
+					clientset:    clientset,
+					kv:           kv,
+					expectations: expectations,
+					stores:       stores,
+					recorder:     record.NewFakeRecorder(100),
+				}
+
+				dsClient.Fake.PrependReactor("patch", "daemonsets", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+					a, ok := action.(testing.PatchAction)
+					Expect(ok).To(BeTrue())
+					patched = true
+
+					patches := []patch.PatchOperation{}
+					Expect(json.Unmarshal(a.GetPatch(), &patches)).To(Succeed())
+
+					patchedDs := &appsv1.DaemonSet{}
+					for _, v := range patches {
+						if v.Path == "/spec" {
+							spec, err := json.Marshal(v.Value)
+							Expect(err).ToNot(HaveOccurred())
+							Expect(json.Unmarshal(spec, &patchedDs.Spec)).To(Succeed())
+						}
+					}
+					Expect(patchedDs.Spec.UpdateStrategy.Type).To(Equal(appsv1.OnDeleteDaemonSetStrategyType))
+					Expect(patchedDs.Spec.UpdateStrategy.RollingUpdate).To(BeNil())
+					return true, patchedDs, nil
+				})
+
+				newDs := daemonSet.DeepCopy()
+				newDs.Spec.UpdateStrategy.Type = appsv1.OnDeleteDaemonSetStrategyType
+				addCustomTargetDeployment(kv, newDs)
+				done, err := r.syncDaemonSet(newDs)
+
+				Expect(patched).To(BeTrue())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(done).To(BeTrue())
+			})
+
 			type daemonSetBuilder func(*v1.KubeVirt, *appsv1.DaemonSet) (current *appsv1.DaemonSet,
 				target *appsv1.DaemonSet)
 			type daemonSetPatchChecker func(*v1.KubeVirt, *appsv1.DaemonSet)
@@ -615,6 +659,55 @@ var _ = Describe("Apply Apps", func() {
 					},
 					CanaryUpgradeStatusUpgradingDaemonSet, false, false, true,
 				),
+				Entry("should restart daemonset rollout with the MaxUnavailable of the target",
+					func(kv *v1.KubeVirt, currentDs *appsv1.DaemonSet) (*appsv1.DaemonSet, *appsv1.DaemonSet) {
+						maxUnavailable := intstr.FromString("25%")
+						newDs := daemonSet.DeepCopy()
+						newDs.Spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateDaemonSet{
+							MaxUnavailable: &maxUnavailable,
+						}
+						addCustomTargetDeployment(kv, newDs)
+						addCustomTargetDeployment(kv, currentDs)
+						markHandlerCanaryReady(daemonSet)
+						currentDs.Spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateDaemonSet{
+							MaxUnavailable: nil,
+						}
+						return currentDs, newDs
+					},
+					func(kv *v1.KubeVirt, daemonSet *appsv1.DaemonSet) {
+						rollingUpdate := daemonSet.Spec.UpdateStrategy.RollingUpdate
+						Expect(rollingUpdate).ToNot(BeNil())
+						Expect(rollingUpdate.MaxUnavailable).ToNot(BeNil())
+						Expect(rollingUpdate.MaxUnavailable.String()).To(Equal("25%"))
+					},
+					CanaryUpgradeStatusUpgradingDaemonSet, false, false, true,
+				),
+				Entry("should restart daemonset rollout with the MaxSurge of the target",
+					func(kv *v1.KubeVirt, currentDs *appsv1.DaemonSet) (*appsv1.DaemonSet, *appsv1.DaemonSet) {
+						maxUnavailable := intstr.FromInt(0)
+						maxSurge := intstr.FromString("20%")
+						newDs := daemonSet.DeepCopy()
+						newDs.Spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateDaemonSet{
+							MaxUnavailable: &maxUnavailable,
+							MaxSurge:       &maxSurge,
+						}
+						addCustomTargetDeployment(kv, newDs)
+						addCustomTargetDeployment(kv, currentDs)
+						markHandlerCanaryReady(daemonSet)
+						currentDs.Spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateDaemonSet{
+							MaxUnavailable: nil,
+						}
+						return currentDs, newDs
+					},
+					func(kv *v1.KubeVirt, daemonSet *appsv1.DaemonSet) {
+						rollingUpdate := daemonSet.Spec.UpdateStrategy.RollingUpdate
+						Expect(rollingUpdate).ToNot(BeNil())
+						Expect(rollingUpdate.MaxUnavailable.IntValue()).To(Equal(0))
+						Expect(rollingUpdate.MaxSurge).ToNot(BeNil())
+						Expect(rollingUpdate.MaxSurge.String()).To(Equal("20%"))
+					},
+					CanaryUpgradeStatusUpgradingDaemonSet, false, false, true,
+				),
 				Entry("should report an error when canary pod fails",
 					func(kv *v1.KubeVirt, currentDs *appsv1.DaemonSet) (*appsv1.DaemonSet, *appsv1.DaemonSet) {
 						newDs := daemonSet.DeepCopy()
@@ -662,6 +755,47 @@ var _ = Describe("Apply Apps", func() {
 					},
 					CanaryUpgradeStatusSuccessful, true, false, true,
 				),
+				Entry("should complete rollout with the MaxUnavailable of the target",
+					func(kv *v1.KubeVirt, currentDs *appsv1.DaemonSet) (*appsv1.DaemonSet, *appsv1.DaemonSet) {
+						maxUnavailable := intstr.FromString("25%")
+						newDs := daemonSet.DeepCopy()
+						newDs.Spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateDaemonSet{
+							MaxUnavailable: &maxUnavailable,
+						}
+						addCustomTargetDeployment(kv, newDs)
+						addCustomTargetDeployment(kv, currentDs)
+						markHandlerReady(daemonSet)
+						currentDs.Spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateDaemonSet{
+							MaxUnavailable: &maxUnavailable,
+						}
+						return currentDs, newDs
+					},
+					func(kv *v1.KubeVirt, daemonSet *appsv1.DaemonSet) {
+						rollingUpdate := daemonSet.Spec.UpdateStrategy.RollingUpdate
+						Expect(rollingUpdate).ToNot(BeNil())
+						Expect(rollingUpdate.MaxUnavailable).ToNot(BeNil())
+						Expect(rollingUpdate.MaxUnavailable.String()).To(Equal("25%"))
+					},
+					CanaryUpgradeStatusSuccessful, true, false, true,
+				),
+				Entry("should not patch the rollout if the target keeps the MaxUnavailable of the canary",
+					func(kv *v1.KubeVirt, currentDs *appsv1.DaemonSet) (*appsv1.DaemonSet, *appsv1.DaemonSet) {
+						maxUnavailable := intstr.FromInt(1)
+						newDs := daemonSet.DeepCopy()
+						newDs.Spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateDaemonSet{
+							MaxUnavailable: &maxUnavailable,
+						}
+						addCustomTargetDeployment(kv, newDs)
+						addCustomTargetDeployment(kv, currentDs)
+						markHandlerCanaryReady(daemonSet)
+						currentDs.Spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateDaemonSet{
+							MaxUnavailable: &maxUnavailable,
+						}
+						return currentDs, newDs
+					},
+					func(kv *v1.KubeVirt, daemonSet *appsv1.DaemonSet) {},
+					CanaryUpgradeStatusWaitingDaemonSetRollout, false, false, false,
+				),
 			)
 		})
 
@@ -80,9 +80,69 @@ func (r *Reconciler) syncKubevirtNamespaceLabels() error {
 	return nil
 }
 
+// createOrUpdateNamespaces creates the namespaces generated for bootstrapping an install and adds missing labels to
+// existing ones. Namespaces are never deleted by the operator.
+func (r *Reconciler) createOrUpdateNamespaces() error {
+	for _, namespace := range r.targetStrategy.NamespaceObjects() {
+		if !r.shouldApplyObject("Namespace", namespace.Name) {
+			continue
+		}
+		if err := r.createOrUpdateNamespace(namespace.DeepCopy()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Reconciler) createOrUpdateNamespace(namespace *corev1.Namespace) error {
+	obj, exists, err := r.stores.NamespaceCache.GetByKey(namespace.Name)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		_, err := r.clientset.CoreV1().Namespaces().Create(context.Background(), namespace, metav1.CreateOptions{})
+		if err != nil && !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("unable to create namespace %+v: %v", namespace, err)
+		}
+		return nil
+	}
+
+	cachedNamespace := obj.(*corev1.Namespace)
+	labelsToPatch := make(map[string]string)
+	for key, value := range namespace.Labels {
+		if cachedValue, ok := cachedNamespace.Labels[key]; !ok || cachedValue != value {
+			labelsToPatch[key] = value
+		}
+	}
+
+	if len(labelsToPatch) == 0 {
+		log.Log.V(4).Infof("namespace %v is up-to-date", namespace.GetName())
+		return nil
+	}
+
+	labelsPatch, err := json.Marshal(labelsToPatch)
+	if err != nil {
+		return err
+	}
+	patch := []byte(fmt.Sprintf(`{"metadata":{"labels": %s}}`, labelsPatch))
+	_, err = r.clientset.CoreV1().Namespaces().Patch(context.Background(), namespace.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to patch namespace %+v: %v", namespace, err)
+	}
+	r.recordPatch("Namespace", "", namespace.Name, patch)
+
+	log.Log.V(2).Infof("namespace %v patched", namespace.GetName())
+	return nil
+}
+
 func (r *Reconciler) createOrUpdateServices() (bool, error) {
 
 	for _, service := range r.targetStrategy.Services() {
+		if !r.shouldApplyObject("Service", service.Name) {
+			continue
+		}
 		pending, err := r.createOrUpdateService(service.DeepCopy())
 		if pending || err != nil {
 			return pending, err
@@ -114,7 +174,17 @@ func (r *Reconciler) createOrUpdateService(service *corev1.Service) (bool, error
 	cachedService := obj.(*corev1.Service)
 
 	deleteAndReplace := hasImmutableFieldChanged(service, cachedService)
+	if !deleteAndReplace && r.reconcileMode == ReconcileModeReplace {
+		patchOps, err := generateServicePatch(cachedService, service.DeepCopy())
+		if err != nil {
+			return false, fmt.Errorf("unable to generate service endpoint patch operations for %+v: %v", service, err)
+		}
+		deleteAndReplace = len(patchOps) > 0
+	}
 	if deleteAndReplace {
+		if err := r.releaseObject(cachedService); err != nil {
+			return false, err
+		}
 		err := deleteService(cachedService, r.kvKey, r.expectations, core)
 		if err != nil {
 			return false, err
@@ -138,6 +208,7 @@ func (r *Reconciler) createOrUpdateService(service *corev1.Service) (bool, error
 	if err != nil {
 		return false, fmt.Errorf("unable to patch service %+v: %v", service, err)
 	}
+	r.recordPatch("Service", service.Namespace, service.Name, generatePatchBytes(patchOps))
 
 	log.Log.V(2).Infof("service %v patched", service.GetName())
 	return false, nil
@@ -258,7 +329,7 @@ func (r *Reconciler) createOrUpdateCertificateSecret(queue workqueue.RateLimitin
 	}
 
 	modified := resourcemerge.BoolPtr(false)
-	resourcemerge.EnsureObjectMeta(modified, &cachedSecret.ObjectMeta, secret.ObjectMeta)
+	ensureObjectMeta(modified, &cachedSecret.ObjectMeta, secret.ObjectMeta)
 
 	if !*modified && !rotateCertificate {
 		log.Log.V(4).Infof("secret %v is up-to-date", secret.GetName())
@@ -274,6 +345,7 @@ func (r *Reconciler) createOrUpdateCertificateSecret(queue workqueue.RateLimitin
 	if err != nil {
 		return nil, fmt.Errorf("unable to patch secret %+v: %v", secret, err)
 	}
+	r.recordPatch("Secret", secret.Namespace, secret.Name, generatePatchBytes(ops))
 
 	log.Log.V(2).Infof("secret %v updated", secret.GetName())
 
@@ -395,7 +467,7 @@ func shouldEnforceClusterIP(desired, current string) bool {
 func getObjectMetaPatch(desired, current metav1.ObjectMeta) ([]string, error) {
 	modified := resourcemerge.BoolPtr(false)
 	existingCopy := current.DeepCopy()
-	resourcemerge.EnsureObjectMeta(modified, existingCopy, desired)
+	ensureObjectMeta(modified, existingCopy, desired)
 
 	labelAnnotationPatch := []string{}
 	var err error
@@ -419,9 +491,79 @@ func hasImmutableFieldChanged(service, cachedService *corev1.Service) bool {
 		deleteAndReplace = true
 	}
 
+	if shouldEnforceIPFamilies(service, cachedService) {
+		deleteAndReplace = true
+	}
+
 	return deleteAndReplace
 }
 
+// shouldEnforceIPFamilies returns true if the desired service explicitly asks for an ipFamilyPolicy or ipFamilies
+// which differ from the current ones
+func shouldEnforceIPFamilies(desired, current *corev1.Service) bool {
+	if desired.Spec.IPFamilyPolicy != nil &&
+		(current.Spec.IPFamilyPolicy == nil || *desired.Spec.IPFamilyPolicy != *current.Spec.IPFamilyPolicy) {
+		return true
+	}
+
+	if len(desired.Spec.IPFamilies) > 0 && !equality.Semantic.DeepEqual(desired.Spec.IPFamilies, current.Spec.IPFamilies) {
+		return true
+	}
+
+	return false
+}
+
+// ServiceChange classifies the kind of change needed to bring a cached service in line with its target
+type ServiceChange int
+
+const (
+	ServiceNoChange ServiceChange = iota
+	ServiceMetadataOnly
+	ServiceSpecPatch
+	ServiceRequiresRecreate
+)
+
+func (c ServiceChange) String() string {
+	switch c {
+	case ServiceNoChange:
+		return "NoChange"
+	case ServiceMetadataOnly:
+		return "MetadataOnly"
+	case ServiceSpecPatch:
+		return "SpecPatch"
+	case ServiceRequiresRecreate:
+		return "RequiresRecreate"
+	default:
+		return fmt.Sprintf("ServiceChange(%d)", int(c))
+	}
+}
+
+// ClassifyServiceChange reports what the reconciler would have to do to the cached service
+// in order to match the target service, without modifying either of them.
+func ClassifyServiceChange(cachedService, service *corev1.Service) (ServiceChange, error) {
+	if hasImmutableFieldChanged(service, cachedService) {
+		return ServiceRequiresRecreate, nil
+	}
+
+	specOps, err := generateServiceSpecPatch(cachedService, service.DeepCopy())
+	if err != nil {
+		return ServiceNoChange, err
+	}
+	if len(specOps) > 0 {
+		return ServiceSpecPatch, nil
+	}
+
+	metaOps, err := getObjectMetaPatch(service.ObjectMeta, cachedService.ObjectMeta)
+	if err != nil {
+		return ServiceNoChange, err
+	}
+	if len(metaOps) > 0 {
+		return ServiceMetadataOnly, nil
+	}
+
+	return ServiceNoChange, nil
+}
+
 func generateServicePatch(
 	cachedService *corev1.Service,
 	service *corev1.Service) ([]string, error) {
@@ -431,11 +573,35 @@ func generateServicePatch(
 		return patchOps, err
 	}
 
+	specOps, err := generateServiceSpecPatch(cachedService, service)
+	if err != nil {
+		return patchOps, err
+	}
+
+	return append(patchOps, specOps...), nil
+}
+
+func generateServiceSpecPatch(cachedService *corev1.Service, service *corev1.Service) ([]string, error) {
+	var patchOps []string
+
 	// set these values in the case they are empty
 	service.Spec.ClusterIP = cachedService.Spec.ClusterIP
 	service.Spec.Type = cachedService.Spec.Type
-	if service.Spec.SessionAffinity == "" {
-		service.Spec.SessionAffinity = cachedService.Spec.SessionAffinity
+	// an unset session affinity is None, a ClientIP affinity which is no longer requested has to be removed
+	if service.Spec.SessionAffinity == "" && cachedService.Spec.SessionAffinity != "" {
+		service.Spec.SessionAffinity = corev1.ServiceAffinityNone
+	}
+	// the API server defaults the timeout of ClientIP session affinity
+	if service.Spec.SessionAffinityConfig == nil &&
+		service.Spec.SessionAffinity == corev1.ServiceAffinityClientIP &&
+		cachedService.Spec.SessionAffinity == corev1.ServiceAffinityClientIP {
+		service.Spec.SessionAffinityConfig = cachedService.Spec.SessionAffinityConfig
+	}
+	if service.Spec.IPFamilyPolicy == nil {
+		service.Spec.IPFamilyPolicy = cachedService.Spec.IPFamilyPolicy
+	}
+	if len(service.Spec.IPFamilies) == 0 {
+		service.Spec.IPFamilies = cachedService.Spec.IPFamilies
 	}
 
 	// If the Specs don't equal each other, replace it
@@ -472,7 +638,7 @@ func (r *Reconciler) createOrUpdateServiceAccount(sa *corev1.ServiceAccount) err
 
 	cachedSa := obj.(*corev1.ServiceAccount)
 	modified := resourcemerge.BoolPtr(false)
-	resourcemerge.EnsureObjectMeta(modified, &cachedSa.ObjectMeta, sa.ObjectMeta)
+	ensureObjectMeta(modified, &cachedSa.ObjectMeta, sa.ObjectMeta)
 	// there was no change to metadata
 	if !*modified {
 		// Up to date
@@ -490,6 +656,7 @@ func (r *Reconciler) createOrUpdateServiceAccount(sa *corev1.ServiceAccount) err
 	if err != nil {
 		return fmt.Errorf("unable to patch serviceaccount %+v: %v", sa, err)
 	}
+	r.recordPatch("ServiceAccount", r.kv.Namespace, sa.Name, generatePatchBytes(labelAnnotationPatch))
 
 	log.Log.V(2).Infof("serviceaccount %v updated", sa.GetName())
 
@@ -501,42 +668,67 @@ func (r *Reconciler) createOrUpdateRbac() error {
 	version, imageRegistry, id := getTargetVersionRegistryID(r.kv)
 
 	// create/update ServiceAccounts
-	for _, sa := range r.targetStrategy.ServiceAccounts() {
-		if err := r.createOrUpdateServiceAccount(sa.DeepCopy()); err != nil {
-			return err
+	if r.shouldApply("ServiceAccount") {
+		for _, sa := range r.targetStrategy.ServiceAccounts() {
+			if !r.shouldApplyObject("ServiceAccount", sa.Name) {
+				continue
+			}
+			if err := r.createOrUpdateServiceAccount(sa.DeepCopy()); err != nil {
+				return err
+			}
 		}
 	}
 
 	// create/update ClusterRoles
-	for _, cr := range r.targetStrategy.ClusterRoles() {
-		err := r.createOrUpdateClusterRole(cr, version, imageRegistry, id)
-		if err != nil {
-			return err
+	if r.shouldApply("ClusterRole") {
+		for _, cr := range r.targetStrategy.ClusterRoles() {
+			if !r.shouldApplyObject("ClusterRole", cr.Name) {
+				continue
+			}
+			err := r.createOrUpdateClusterRole(cr, version, imageRegistry, id)
+			if err != nil {
+				return err
+			}
 		}
 	}
 
 	// create/update ClusterRoleBindings
-	for _, crb := range r.targetStrategy.ClusterRoleBindings() {
-		err := r.createOrUpdateClusterRoleBinding(crb, version, imageRegistry, id)
-		if err != nil {
-			return err
-		}
+	if r.shouldApply("ClusterRoleBinding") {
+		for _, crb := range r.targetStrategy.ClusterRoleBindings() {
+			if !r.shouldApplyObject("ClusterRoleBinding", crb.Name) {
+				continue
+			}
+			err := r.createOrUpdateClusterRoleBinding(crb, version, imageRegistry, id)
+			if err != nil {
+				return err
+			}
 
+		}
 	}
 
 	// create/update Roles
-	for _, role := range r.targetStrategy.Roles() {
-		err := r.createOrUpdateRole(role, version, imageRegistry, id)
-		if err != nil {
-			return err
+	if r.shouldApply("Role") {
+		for _, role := range r.targetStrategy.Roles() {
+			if !r.shouldApplyObject("Role", role.Name) {
+				continue
+			}
+			err := r.createOrUpdateRole(role, version, imageRegistry, id)
+			if err != nil {
+				return err
+			}
 		}
 	}
 
 	// create/update RoleBindings
-	for _, rb := range r.targetStrategy.RoleBindings() {
-		err := r.createOrUpdateRoleBinding(rb, version, imageRegistry, id)
-		if err != nil {
-			return err
+	if r.shouldApply("RoleBinding") {
+		for _, rb := range r.targetStrategy.RoleBindings() {
+			if !r.shouldApplyObject("RoleBinding", rb.Name) {
+				continue
+			}
+			err := r.createOrUpdateRoleBinding(rb, version, imageRegistry, id)
+			if err != nil {
+				return err
+			}
 		}
 	}
 
@@ -614,7 +806,7 @@ func (r *Reconciler) createOrUpdateKubeVirtCAConfigMap(queue workqueue.RateLimit
 	}
 
 	modified := resourcemerge.BoolPtr(false)
-	resourcemerge.EnsureObjectMeta(modified, &existing.DeepCopy().ObjectMeta, configMap.ObjectMeta)
+	ensureObjectMeta(modified, &existing.DeepCopy().ObjectMeta, configMap.ObjectMeta)
 
 	if !*modified && !updateBundle {
 		log.Log.V(4).Infof("configMap %v is up-to-date", configMap.GetName())
@@ -630,12 +822,119 @@ func (r *Reconciler) createOrUpdateKubeVirtCAConfigMap(queue workqueue.RateLimit
 	if err != nil {
 		return nil, fmt.Errorf("unable to patch configMap %+v: %v", configMap, err)
 	}
+	r.recordPatch("ConfigMap", configMap.Namespace, configMap.Name, generatePatchBytes(ops))
 
 	log.Log.V(2).Infof("configMap %v updated", configMap.GetName())
 
 	return []byte(configMap.Data[components.CABundleKey]), nil
 }
 
+func (r *Reconciler) createOrUpdateConfigMaps() error {
+	for _, configMap := range r.targetStrategy.ConfigMaps() {
+		if isCAConfigMap(configMap.Name) {
+			continue
+		}
+		if !r.shouldApplyObject("ConfigMap", configMap.Name) {
+			continue
+		}
+
+		if err := r.createOrUpdateConfigMap(configMap.DeepCopy()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isCAConfigMap returns true for the CA config maps, they are populated and reconciled together with the certificates
+func isCAConfigMap(name string) bool {
+	return name == components.KubeVirtCASecretName || name == components.KubeVirtExportCASecretName
+}
+
+func (r *Reconciler) createOrUpdateConfigMap(configMap *corev1.ConfigMap) error {
+	version, imageRegistry, id := getTargetVersionRegistryID(r.kv)
+	injectOperatorMetadata(r.kv, &configMap.ObjectMeta, version, imageRegistry, id, true)
+
+	obj, exists, _ := r.stores.ConfigMapCache.Get(configMap)
+	if !exists {
+		r.expectations.ConfigMap.RaiseExpectations(r.kvKey, 1, 0)
+		_, err := r.clientset.CoreV1().ConfigMaps(configMap.Namespace).Create(context.Background(), configMap, metav1.CreateOptions{})
+		if err != nil {
+			r.expectations.ConfigMap.LowerExpectations(r.kvKey, 1, 0)
+			return fmt.Errorf("unable to create configMap %+v: %v", configMap, err)
+		}
+		log.Log.V(2).Infof("configMap %v created", configMap.GetName())
+		return nil
+	}
+
+	existing := obj.(*corev1.ConfigMap)
+	modified := resourcemerge.BoolPtr(false)
+	ensureObjectMeta(modified, &existing.DeepCopy().ObjectMeta, configMap.ObjectMeta)
+
+	if !*modified &&
+		equality.Semantic.DeepEqual(existing.Data, configMap.Data) &&
+		equality.Semantic.DeepEqual(existing.BinaryData, configMap.BinaryData) &&
+		isImmutable(existing) == isImmutable(configMap) {
+		log.Log.V(4).Infof("configMap %v is up-to-date", configMap.GetName())
+		return nil
+	}
+
+	if isImmutable(existing) || isImmutable(configMap) {
+		// immutable config maps can not be patched, the operator
+		// will recreate it once the old one is deleted
+		if err := r.releaseObject(existing); err != nil {
+			return err
+		}
+		return deleteConfigMap(existing, r.kvKey, r.expectations, r.clientset.CoreV1())
+	}
+
+	ops, err := createConfigMapPatch(configMap)
+	if err != nil {
+		return err
+	}
+	if configMap.Data == nil && existing.Data != nil {
+		ops = append(ops, `{ "op": "remove", "path": "/data" }`)
+	}
+	if configMap.BinaryData == nil && existing.BinaryData != nil {
+		ops = append(ops, `{ "op": "remove", "path": "/binaryData" }`)
+	}
+
+	_, err = r.clientset.CoreV1().ConfigMaps(configMap.Namespace).Patch(context.Background(), configMap.Name, types.JSONPatchType, generatePatchBytes(ops), metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to patch configMap %+v: %v", configMap, err)
+	}
+	r.recordPatch("ConfigMap", configMap.Namespace, configMap.Name, generatePatchBytes(ops))
+
+	log.Log.V(2).Infof("configMap %v updated", configMap.GetName())
+	return nil
+}
+
+func isImmutable(configMap *corev1.ConfigMap) bool {
+	return configMap.Immutable != nil && *configMap.Immutable
+}
+
+func deleteConfigMap(configMap *corev1.ConfigMap, kvKey string, expectations *util.Expectations, core typedv1.CoreV1Interface) error {
+	if configMap.DeletionTimestamp != nil {
+		return nil
+	}
+
+	key, err := controller.KeyFunc(configMap)
+	if err != nil {
+		return err
+	}
+
+	expectations.ConfigMap.AddExpectedDeletion(kvKey, key)
+	err = core.ConfigMaps(configMap.Namespace).Delete(context.Background(), configMap.Name, metav1.DeleteOptions{})
+	if err != nil {
+		expectations.ConfigMap.DeletionObserved(kvKey, key)
+		log.Log.Errorf("Failed to delete configMap %+v: %v", configMap, err)
+		return err
+	}
+
+	log.Log.V(2).Infof("configMap %v deleted. It must be re-created", configMap.GetName())
+	return nil
+}
+
 func createConfigMapPatch(configMap *corev1.ConfigMap) ([]string, error) {
 	// Patch if old version
 	var ops []string
@@ -647,12 +946,23 @@ func createConfigMapPatch(configMap *corev1.ConfigMap) ([]string, error) {
 	}
 	ops = append(ops, labelAnnotationPatch...)
 
-	// Add Spec Patch
-	data, err := json.Marshal(configMap.Data)
-	if err != nil {
-		return ops, err
+	// Add Spec Patch, add also replaces the data if the cached config map has some
+	if configMap.Data != nil {
+		data, err := json.Marshal(configMap.Data)
+		if err != nil {
+			return ops, err
+		}
+		ops = append(ops, fmt.Sprintf(`{ "op": "add", "path": "/data", "value": %s }`, string(data)))
+	}
+
+	if configMap.BinaryData != nil {
+		// encoded as base64 strings, like the API server expects them
+		binaryData, err := json.Marshal(configMap.BinaryData)
+		if err != nil {
+			return ops, err
+		}
+		ops = append(ops, fmt.Sprintf(`{ "op": "add", "path": "/binaryData", "value": %s }`, string(binaryData)))
 	}
-	ops = append(ops, fmt.Sprintf(`{ "op": "replace", "path": "/data", "value": %s }`, string(data)))
 
 	return ops, nil
 }
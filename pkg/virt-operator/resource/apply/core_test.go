@@ -22,6 +22,7 @@ package apply
 import (
 	"crypto/tls"
 	"encoding/json"
+	"fmt"
 	"strings"
 	"time"
 
@@ -37,6 +38,7 @@ import (
 
 	"kubevirt.io/kubevirt/pkg/certificates/triple"
 	"kubevirt.io/kubevirt/pkg/certificates/triple/cert"
+	"kubevirt.io/kubevirt/pkg/controller"
 	"kubevirt.io/kubevirt/pkg/virt-operator/resource/generate/components"
 	"kubevirt.io/kubevirt/pkg/virt-operator/util"
 
@@ -89,6 +91,208 @@ var _ = Describe("Apply", func() {
 			deleteAndReplace := hasImmutableFieldChanged(service, cachedService)
 			Expect(deleteAndReplace).To(BeTrue())
 		})
+
+		It("should patch the metadata of a LoadBalancer service when its annotations change", func() {
+			cachedService := &corev1.Service{}
+			cachedService.Spec.Type = corev1.ServiceTypeLoadBalancer
+			cachedService.Annotations = map[string]string{"service.beta.kubernetes.io/aws-load-balancer-internal": "false"}
+
+			service := cachedService.DeepCopy()
+			service.Annotations["service.beta.kubernetes.io/aws-load-balancer-internal"] = "true"
+
+			ops, err := generateServicePatch(cachedService, service)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ops).To(ContainElement(And(
+				ContainSubstring(`"path": "/metadata/annotations"`),
+				ContainSubstring(`"service.beta.kubernetes.io/aws-load-balancer-internal":"true"`),
+			)))
+		})
+
+		Context("with ClientIP session affinity", func() {
+			newAffinityService := func(timeoutSeconds int32) *corev1.Service {
+				service := &corev1.Service{}
+				service.Spec.Type = corev1.ServiceTypeClusterIP
+				service.Spec.ClusterIP = "10.10.10.10"
+				components.SetClientIPSessionAffinity(service, timeoutSeconds)
+				return service
+			}
+
+			It("should not patch an unchanged timeout", func() {
+				Expect(generateServicePatch(newAffinityService(3600), newAffinityService(3600))).To(BeEmpty())
+			})
+
+			It("should not patch the defaulted config of an affinity without timeout", func() {
+				cachedService := newAffinityService(corev1.DefaultClientIPServiceAffinitySeconds)
+				service := cachedService.DeepCopy()
+				service.Spec.SessionAffinityConfig = nil
+
+				Expect(generateServicePatch(cachedService, service)).To(BeEmpty())
+			})
+
+			It("should patch the spec when the timeout changes", func() {
+				ops, err := generateServicePatch(newAffinityService(3600), newAffinityService(60))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(ops).To(ConsistOf(And(
+					ContainSubstring(`"path": "/spec"`),
+					ContainSubstring(`"sessionAffinityConfig":{"clientIP":{"timeoutSeconds":60}}`),
+				)))
+			})
+
+			It("should remove the affinity when it is no longer requested", func() {
+				service := &corev1.Service{}
+				service.Spec.Type = corev1.ServiceTypeClusterIP
+
+				ops, err := generateServicePatch(newAffinityService(3600), service)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(ops).To(ConsistOf(And(
+					ContainSubstring(`"path": "/spec"`),
+					ContainSubstring(`"sessionAffinity":"None"`),
+					Not(ContainSubstring(`"sessionAffinityConfig"`)),
+				)))
+			})
+
+			It("should not patch a service without affinity", func() {
+				cachedService := &corev1.Service{}
+				cachedService.Spec.Type = corev1.ServiceTypeClusterIP
+				cachedService.Spec.ClusterIP = "10.10.10.10"
+				cachedService.Spec.SessionAffinity = corev1.ServiceAffinityNone
+
+				service := &corev1.Service{}
+				service.Spec.Type = corev1.ServiceTypeClusterIP
+
+				Expect(generateServicePatch(cachedService, service)).To(BeEmpty())
+			})
+		})
+
+		DescribeTable("should classify the change", func(cachedService, service *corev1.Service, expected ServiceChange) {
+			serviceCopy := service.DeepCopy()
+
+			change, err := ClassifyServiceChange(cachedService, service)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(change).To(Equal(expected))
+			Expect(service).To(Equal(serviceCopy))
+		},
+			Entry("as no change with identical services",
+				&corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "test"}},
+					Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP, ClusterIP: "10.10.10.10"},
+				},
+				&corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "test"}},
+					Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP},
+				},
+				ServiceNoChange,
+			),
+			Entry("as metadata only when only labels differ",
+				&corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "old"}},
+					Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP, ClusterIP: "10.10.10.10"},
+				},
+				&corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "new"}},
+					Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP},
+				},
+				ServiceMetadataOnly,
+			),
+			Entry("as spec patch when the selector differs",
+				&corev1.Service{
+					Spec: corev1.ServiceSpec{
+						Type:      corev1.ServiceTypeClusterIP,
+						ClusterIP: "10.10.10.10",
+						Selector:  map[string]string{v1.AppLabel: "virt-api"},
+					},
+				},
+				&corev1.Service{
+					Spec: corev1.ServiceSpec{
+						Type:     corev1.ServiceTypeClusterIP,
+						Selector: map[string]string{v1.AppLabel: "virt-controller"},
+					},
+				},
+				ServiceSpecPatch,
+			),
+			Entry("as requiring recreation when the ClusterIP changes",
+				&corev1.Service{
+					Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP, ClusterIP: "10.10.10.10"},
+				},
+				&corev1.Service{
+					Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP, ClusterIP: "10.10.10.11"},
+				},
+				ServiceRequiresRecreate,
+			),
+			Entry("as requiring recreation when the type is not ClusterIP",
+				&corev1.Service{
+					Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP},
+				},
+				&corev1.Service{
+					Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeNodePort},
+				},
+				ServiceRequiresRecreate,
+			),
+			Entry("as requiring recreation when the ipFamilyPolicy changes",
+				&corev1.Service{
+					Spec: corev1.ServiceSpec{
+						Type:           corev1.ServiceTypeClusterIP,
+						ClusterIP:      "10.10.10.10",
+						IPFamilyPolicy: ipFamilyPolicyPtr(corev1.IPFamilyPolicySingleStack),
+						IPFamilies:     []corev1.IPFamily{corev1.IPv4Protocol},
+					},
+				},
+				&corev1.Service{
+					Spec: corev1.ServiceSpec{
+						Type:           corev1.ServiceTypeClusterIP,
+						IPFamilyPolicy: ipFamilyPolicyPtr(corev1.IPFamilyPolicyPreferDualStack),
+					},
+				},
+				ServiceRequiresRecreate,
+			),
+			Entry("as requiring recreation when the ipFamilies change",
+				&corev1.Service{
+					Spec: corev1.ServiceSpec{
+						Type:       corev1.ServiceTypeClusterIP,
+						ClusterIP:  "10.10.10.10",
+						IPFamilies: []corev1.IPFamily{corev1.IPv4Protocol},
+					},
+				},
+				&corev1.Service{
+					Spec: corev1.ServiceSpec{
+						Type:       corev1.ServiceTypeClusterIP,
+						IPFamilies: []corev1.IPFamily{corev1.IPv6Protocol},
+					},
+				},
+				ServiceRequiresRecreate,
+			),
+			Entry("as no change when the ipFamilyPolicy is identical",
+				&corev1.Service{
+					Spec: corev1.ServiceSpec{
+						Type:           corev1.ServiceTypeClusterIP,
+						ClusterIP:      "10.10.10.10",
+						IPFamilyPolicy: ipFamilyPolicyPtr(corev1.IPFamilyPolicyPreferDualStack),
+						IPFamilies:     []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol},
+					},
+				},
+				&corev1.Service{
+					Spec: corev1.ServiceSpec{
+						Type:           corev1.ServiceTypeClusterIP,
+						IPFamilyPolicy: ipFamilyPolicyPtr(corev1.IPFamilyPolicyPreferDualStack),
+					},
+				},
+				ServiceNoChange,
+			),
+			Entry("as no change when the ipFamilies are defaulted by the server",
+				&corev1.Service{
+					Spec: corev1.ServiceSpec{
+						Type:           corev1.ServiceTypeClusterIP,
+						ClusterIP:      "10.10.10.10",
+						IPFamilyPolicy: ipFamilyPolicyPtr(corev1.IPFamilyPolicySingleStack),
+						IPFamilies:     []corev1.IPFamily{corev1.IPv4Protocol},
+					},
+				},
+				&corev1.Service{
+					Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP},
+				},
+				ServiceNoChange,
+			),
+		)
 	})
 
 	Context("should reconcile configmap", func() {
@@ -271,6 +475,141 @@ var _ = Describe("Apply", func() {
 			Expect(err).ToNot(HaveOccurred())
 			Expect(patched).To(BeTrue())
 		})
+
+		It("should recreate rather than patch an immutable ConfigMap on change", func() {
+			immutable := true
+			requiredCM := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "immutable",
+					Namespace: operatorNamespace,
+				},
+				Immutable: &immutable,
+				Data:      map[string]string{"key": "new"},
+			}
+			version, imageRegistry, id := getTargetVersionRegistryID(kv)
+			existingCM := requiredCM.DeepCopy()
+			injectOperatorMetadata(kv, &existingCM.ObjectMeta, version, imageRegistry, id, true)
+			existingCM.Data = map[string]string{"key": "old"}
+			Expect(stores.ConfigMapCache.Add(existingCM)).To(Succeed())
+
+			expectations.ConfigMap = controller.NewUIDTrackingControllerExpectations(controller.NewControllerExpectationsWithName("ConfigMap"))
+			r := &Reconciler{
+				kv:           kv,
+				stores:       stores,
+				clientset:    clientset,
+				expectations: expectations,
+			}
+
+			deleted := false
+			coreclientset.Fake.PrependReactor("delete", "configmaps", func(action testing.Action) (handled bool, ret runtime.Object, err error) {
+				Expect(action.(testing.DeleteAction).GetName()).To(Equal("immutable"))
+				deleted = true
+				return true, nil, nil
+			})
+
+			Expect(r.createOrUpdateConfigMap(requiredCM)).To(Succeed())
+			Expect(deleted).To(BeTrue())
+		})
+
+		It("should patch changed binaryData", func() {
+			requiredCM := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "policy",
+					Namespace: operatorNamespace,
+				},
+				BinaryData: map[string][]byte{"policy.wasm": {0x00, 0x61, 0x73, 0x6d, 0xff}},
+			}
+			version, imageRegistry, id := getTargetVersionRegistryID(kv)
+			existingCM := requiredCM.DeepCopy()
+			injectOperatorMetadata(kv, &existingCM.ObjectMeta, version, imageRegistry, id, true)
+			existingCM.BinaryData = map[string][]byte{"policy.wasm": {0x00}}
+			Expect(stores.ConfigMapCache.Add(existingCM)).To(Succeed())
+
+			r := &Reconciler{
+				kv:           kv,
+				stores:       stores,
+				clientset:    clientset,
+				expectations: expectations,
+			}
+
+			var patch []byte
+			coreclientset.Fake.PrependReactor("patch", "configmaps", func(action testing.Action) (handled bool, ret runtime.Object, err error) {
+				patch = action.(testing.PatchAction).GetPatch()
+				return true, &corev1.ConfigMap{}, nil
+			})
+
+			Expect(r.createOrUpdateConfigMap(requiredCM.DeepCopy())).To(Succeed())
+			Expect(string(patch)).To(ContainSubstring(`{ "op": "add", "path": "/binaryData", "value": {"policy.wasm":"AGFzbf8="} }`))
+
+			// an up-to-date config map is not patched again
+			patch = nil
+			existingCM = existingCM.DeepCopy()
+			existingCM.BinaryData = requiredCM.BinaryData
+			Expect(stores.ConfigMapCache.Update(existingCM)).To(Succeed())
+			Expect(r.createOrUpdateConfigMap(requiredCM.DeepCopy())).To(Succeed())
+			Expect(patch).To(BeNil())
+		})
+
+		It("should remove data which is no longer required", func() {
+			requiredCM := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "policy",
+					Namespace: operatorNamespace,
+				},
+				BinaryData: map[string][]byte{"policy.wasm": {0x00}},
+			}
+			version, imageRegistry, id := getTargetVersionRegistryID(kv)
+			existingCM := requiredCM.DeepCopy()
+			injectOperatorMetadata(kv, &existingCM.ObjectMeta, version, imageRegistry, id, true)
+			existingCM.Data = map[string]string{"key": "old"}
+			Expect(stores.ConfigMapCache.Add(existingCM)).To(Succeed())
+
+			r := &Reconciler{
+				kv:           kv,
+				stores:       stores,
+				clientset:    clientset,
+				expectations: expectations,
+			}
+
+			var patch []byte
+			coreclientset.Fake.PrependReactor("patch", "configmaps", func(action testing.Action) (handled bool, ret runtime.Object, err error) {
+				patch = action.(testing.PatchAction).GetPatch()
+				return true, &corev1.ConfigMap{}, nil
+			})
+
+			Expect(r.createOrUpdateConfigMap(requiredCM.DeepCopy())).To(Succeed())
+			Expect(string(patch)).To(ContainSubstring(`{ "op": "remove", "path": "/data" }`))
+		})
+
+		DescribeTable("should generate patches which apply to the cached config map", func(existingCM, requiredCM *corev1.ConfigMap) {
+			ops, err := createConfigMapPatch(requiredCM)
+			Expect(err).ToNot(HaveOccurred())
+			patch, err := jsonpatch.DecodePatch([]byte(fmt.Sprintf("[%s]", strings.Join(ops, ","))))
+			Expect(err).ToNot(HaveOccurred())
+
+			obj, err := json.Marshal(existingCM)
+			Expect(err).ToNot(HaveOccurred())
+			obj, err = patch.Apply(obj)
+			Expect(err).ToNot(HaveOccurred())
+
+			patchedCM := &corev1.ConfigMap{}
+			Expect(json.Unmarshal(obj, patchedCM)).To(Succeed())
+			Expect(patchedCM.Data).To(Equal(requiredCM.Data))
+			Expect(patchedCM.BinaryData).To(Equal(requiredCM.BinaryData))
+		},
+			Entry("with changed data",
+				&corev1.ConfigMap{Data: map[string]string{"key": "old"}},
+				&corev1.ConfigMap{Data: map[string]string{"key": "new"}},
+			),
+			Entry("with data added to a config map without data",
+				&corev1.ConfigMap{BinaryData: map[string][]byte{"policy.wasm": {0x00}}},
+				&corev1.ConfigMap{Data: map[string]string{"key": "new"}, BinaryData: map[string][]byte{"policy.wasm": {0x00}}},
+			),
+			Entry("with only binaryData",
+				&corev1.ConfigMap{BinaryData: map[string][]byte{"policy.wasm": {0x00}}},
+				&corev1.ConfigMap{BinaryData: map[string][]byte{"policy.wasm": {0x00, 0x61}}},
+			),
+		)
 	})
 
 	Context("should reconcile service account", func() {
@@ -745,3 +1084,7 @@ var _ = Describe("Apply", func() {
 		)
 	})
 })
+
+func ipFamilyPolicyPtr(policy corev1.IPFamilyPolicy) *corev1.IPFamilyPolicy {
+	return &policy
+}
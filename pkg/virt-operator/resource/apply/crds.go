@@ -64,6 +64,9 @@ func patchCRD(client clientset.Interface, crd *extv1.CustomResourceDefinition, o
 
 func (r *Reconciler) createOrUpdateCrds() error {
 	for _, crd := range r.targetStrategy.CRDs() {
+		if !r.shouldApplyObject("CustomResourceDefinition", crd.Name) {
+			continue
+		}
 		err := r.createOrUpdateCrd(crd)
 		if err != nil {
 			return err
@@ -99,7 +102,7 @@ func (r *Reconciler) createOrUpdateCrd(crd *extv1.CustomResourceDefinition) erro
 	modified := resourcemerge.BoolPtr(false)
 	expectedGeneration := GetExpectedGeneration(crd, r.kv.Status.Generations)
 
-	resourcemerge.EnsureObjectMeta(modified, &cachedCrd.ObjectMeta, crd.ObjectMeta)
+	ensureObjectMeta(modified, &cachedCrd.ObjectMeta, crd.ObjectMeta)
 	// there was no change to metadata, the generation was right
 	if !*modified && cachedCrd.GetGeneration() == expectedGeneration {
 		log.Log.V(4).Infof("crd %v is up-to-date", crd.GetName())
@@ -122,6 +125,7 @@ func (r *Reconciler) createOrUpdateCrd(crd *extv1.CustomResourceDefinition) erro
 	if crd, err = patchCRD(client, crd, ops); err != nil {
 		return err
 	}
+	r.recordPatch("CustomResourceDefinition", "", crd.Name, generatePatchBytes(ops))
 
 	SetGeneration(&r.kv.Status.Generations, crd)
 
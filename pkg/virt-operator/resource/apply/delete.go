@@ -34,8 +34,12 @@ import (
 	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
 	apiregv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
 
 	v1 "kubevirt.io/api/core/v1"
@@ -108,6 +112,14 @@ func DeleteAll(kv *v1.KubeVirt,
 		GracePeriodSeconds: &gracePeriod,
 	}
 
+	// objects can't go away before the operator releases them
+	if finalizer := util.GetTargetConfigFromKV(kv).GetObjectFinalizer(); finalizer != "" {
+		err = removeObjectFinalizer(finalizer, stores, clientset.DynamicClient())
+		if err != nil {
+			return err
+		}
+	}
+
 	// first delete CRDs only
 	err = crdHandleDeletion(kvkey, stores, clientset, expectations)
 	if err != nil {
@@ -618,3 +630,134 @@ func crdHandleDeletion(kvkey string,
 
 	return nil
 }
+
+// removeObjectFinalizer removes the finalizer from all cached objects, in the reverse order in which the reconciler
+// applies them
+func removeObjectFinalizer(finalizer string, stores util.Stores, client dynamic.Interface) error {
+	reverseApplyOrder := []cache.Store{
+		stores.DaemonSetCache,
+		stores.DeploymentCache,
+		stores.RouteCache,
+		stores.SecretCache,
+		stores.APIServiceCache,
+		stores.MutatingWebhookCache,
+		stores.ValidationWebhookCache,
+		stores.ServiceCache,
+		stores.ConfigMapCache,
+		stores.SCCCache,
+		stores.RoleBindingCache,
+		stores.RoleCache,
+		stores.ClusterRoleBindingCache,
+		stores.ClusterRoleCache,
+		stores.ServiceAccountCache,
+		stores.PrometheusRuleCache,
+		stores.ServiceMonitorCache,
+	}
+
+	for _, store := range reverseApplyOrder {
+		if store == nil {
+			continue
+		}
+		for _, obj := range store.List() {
+			if err := removeFinalizer(obj, finalizer, client); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// releaseObject removes the object finalizer from obj before the reconciler deletes it, the deletion would be
+// blocked otherwise
+func (r *Reconciler) releaseObject(obj interface{}) error {
+	if r.objectFinalizer == "" {
+		return nil
+	}
+	return removeFinalizer(obj, r.objectFinalizer, r.clientset.DynamicClient())
+}
+
+// removeFinalizer removes the finalizer from obj with a patch which fails if the finalizers changed in the meantime
+func removeFinalizer(obj interface{}, finalizer string, client dynamic.Interface) error {
+	object, ok := obj.(metav1.Object)
+	if !ok {
+		return fmt.Errorf(castFailedFmt, obj)
+	}
+	if !controller.HasFinalizer(object, finalizer) {
+		return nil
+	}
+	gvr, ok := groupVersionResourceFor(obj)
+	if !ok {
+		return nil
+	}
+
+	oldPatchBytes, err := json.Marshal(object.GetFinalizers())
+	if err != nil {
+		return err
+	}
+	finalizers := []string{}
+	for _, f := range object.GetFinalizers() {
+		if f != finalizer {
+			finalizers = append(finalizers, f)
+		}
+	}
+	newPatchBytes, err := json.Marshal(finalizers)
+	if err != nil {
+		return err
+	}
+	ops := fmt.Sprintf(`[{ "op": "test", "path": "%s", "value": %s }, { "op": "replace", "path": "%s", "value": %s }]`,
+		finalizerPath,
+		string(oldPatchBytes),
+		finalizerPath,
+		string(newPatchBytes))
+
+	var resource dynamic.ResourceInterface = client.Resource(gvr)
+	if object.GetNamespace() != "" {
+		resource = client.Resource(gvr).Namespace(object.GetNamespace())
+	}
+	_, err = resource.Patch(context.Background(), object.GetName(), types.JSONPatchType, []byte(ops), metav1.PatchOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("unable to remove finalizer %s from %s %s: %v", finalizer, gvr.Resource, object.GetName(), err)
+	}
+	return nil
+}
+
+func groupVersionResourceFor(obj interface{}) (schema.GroupVersionResource, bool) {
+	switch obj.(type) {
+	case *appsv1.DaemonSet:
+		return appsv1.SchemeGroupVersion.WithResource("daemonsets"), true
+	case *appsv1.Deployment:
+		return appsv1.SchemeGroupVersion.WithResource("deployments"), true
+	case *routev1.Route:
+		return routev1.GroupVersion.WithResource("routes"), true
+	case *corev1.Secret:
+		return corev1.SchemeGroupVersion.WithResource("secrets"), true
+	case *apiregv1.APIService:
+		return apiregv1.SchemeGroupVersion.WithResource("apiservices"), true
+	case *admissionregistrationv1.MutatingWebhookConfiguration:
+		return admissionregistrationv1.SchemeGroupVersion.WithResource("mutatingwebhookconfigurations"), true
+	case *admissionregistrationv1.ValidatingWebhookConfiguration:
+		return admissionregistrationv1.SchemeGroupVersion.WithResource("validatingwebhookconfigurations"), true
+	case *corev1.Service:
+		return corev1.SchemeGroupVersion.WithResource("services"), true
+	case *corev1.ConfigMap:
+		return corev1.SchemeGroupVersion.WithResource("configmaps"), true
+	case *secv1.SecurityContextConstraints:
+		return secv1.GroupVersion.WithResource("securitycontextconstraints"), true
+	case *rbacv1.RoleBinding:
+		return rbacv1.SchemeGroupVersion.WithResource("rolebindings"), true
+	case *rbacv1.Role:
+		return rbacv1.SchemeGroupVersion.WithResource("roles"), true
+	case *rbacv1.ClusterRoleBinding:
+		return rbacv1.SchemeGroupVersion.WithResource("clusterrolebindings"), true
+	case *rbacv1.ClusterRole:
+		return rbacv1.SchemeGroupVersion.WithResource("clusterroles"), true
+	case *corev1.ServiceAccount:
+		return corev1.SchemeGroupVersion.WithResource("serviceaccounts"), true
+	case *promv1.PrometheusRule:
+		return promv1.SchemeGroupVersion.WithResource("prometheusrules"), true
+	case *promv1.ServiceMonitor:
+		return promv1.SchemeGroupVersion.WithResource("servicemonitors"), true
+	}
+	return schema.GroupVersionResource{}, false
+}
@@ -20,12 +20,25 @@
 package apply
 
 import (
+	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
 
 	v1 "kubevirt.io/api/core/v1"
+	"kubevirt.io/client-go/kubecli"
+
+	"kubevirt.io/kubevirt/pkg/controller"
+	"kubevirt.io/kubevirt/pkg/virt-operator/resource/generate/install"
+	"kubevirt.io/kubevirt/pkg/virt-operator/util"
 )
 
 var _ = Describe("Deletion", func() {
@@ -171,6 +184,106 @@ var _ = Describe("Deletion", func() {
 	})
 })
 
+var _ = Describe("Object finalizer removal", func() {
+
+	const finalizer = "example.org/teardown"
+
+	It("should remove the finalizer in reverse apply order", func() {
+		stores := util.Stores{}
+		stores.DaemonSetCache = cache.NewStore(cache.MetaNamespaceKeyFunc)
+		stores.ServiceAccountCache = cache.NewStore(cache.MetaNamespaceKeyFunc)
+		stores.ConfigMapCache = cache.NewStore(cache.MetaNamespaceKeyFunc)
+
+		Expect(stores.ServiceAccountCache.Add(&corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{Name: "sa", Namespace: "ns", Finalizers: []string{"other", finalizer}},
+		})).To(Succeed())
+		Expect(stores.DaemonSetCache.Add(&appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "ds", Namespace: "ns", Finalizers: []string{finalizer}},
+		})).To(Succeed())
+		Expect(stores.ConfigMapCache.Add(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "ns"},
+		})).To(Succeed())
+
+		client := fakedynamic.NewSimpleDynamicClient(runtime.NewScheme())
+		var patched []string
+		var patches []string
+		client.PrependReactor("patch", "*", func(action testing.Action) (handled bool, ret runtime.Object, err error) {
+			patchAction := action.(testing.PatchAction)
+			patched = append(patched, action.GetResource().Resource+"/"+patchAction.GetName())
+			patches = append(patches, string(patchAction.GetPatch()))
+			return true, nil, nil
+		})
+
+		Expect(removeObjectFinalizer(finalizer, stores, client)).To(Succeed())
+		Expect(patched).To(Equal([]string{"daemonsets/ds", "serviceaccounts/sa"}))
+		Expect(patches[0]).To(ContainSubstring(`"path": "/metadata/finalizers", "value": []`))
+		Expect(patches[1]).To(ContainSubstring(`"path": "/metadata/finalizers", "value": ["other"]`))
+	})
+
+	It("should fail on objects which are not Kubernetes objects", func() {
+		client := fakedynamic.NewSimpleDynamicClient(runtime.NewScheme())
+		Expect(removeFinalizer("not-an-object", finalizer, client)).ToNot(Succeed())
+	})
+
+	It("should remove the finalizer from objects dropped from the install strategy before deleting them", func() {
+		ctrl := gomock.NewController(GinkgoT())
+
+		stores := util.Stores{
+			ValidationWebhookCache:  cache.NewStore(cache.MetaNamespaceKeyFunc),
+			MutatingWebhookCache:    cache.NewStore(cache.MetaNamespaceKeyFunc),
+			APIServiceCache:         cache.NewStore(cache.MetaNamespaceKeyFunc),
+			SecretCache:             cache.NewStore(cache.MetaNamespaceKeyFunc),
+			ConfigMapCache:          cache.NewStore(cache.MetaNamespaceKeyFunc),
+			CrdCache:                cache.NewStore(cache.MetaNamespaceKeyFunc),
+			DaemonSetCache:          cache.NewStore(cache.MetaNamespaceKeyFunc),
+			DeploymentCache:         cache.NewStore(cache.MetaNamespaceKeyFunc),
+			ServiceCache:            cache.NewStore(cache.MetaNamespaceKeyFunc),
+			ClusterRoleBindingCache: cache.NewStore(cache.MetaNamespaceKeyFunc),
+			ClusterRoleCache:        cache.NewStore(cache.MetaNamespaceKeyFunc),
+			RoleBindingCache:        cache.NewStore(cache.MetaNamespaceKeyFunc),
+			RoleCache:               cache.NewStore(cache.MetaNamespaceKeyFunc),
+			ServiceAccountCache:     cache.NewStore(cache.MetaNamespaceKeyFunc),
+			SCCCache:                cache.NewStore(cache.MetaNamespaceKeyFunc),
+			PrometheusRuleCache:     cache.NewStore(cache.MetaNamespaceKeyFunc),
+			ServiceMonitorCache:     cache.NewStore(cache.MetaNamespaceKeyFunc),
+		}
+		Expect(stores.ServiceAccountCache.Add(&corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{Name: "outdated", Namespace: "ns", Finalizers: []string{finalizer}},
+		})).To(Succeed())
+
+		var actions []string
+		dynamicClient := fakedynamic.NewSimpleDynamicClient(runtime.NewScheme())
+		dynamicClient.PrependReactor("patch", "*", func(action testing.Action) (handled bool, ret runtime.Object, err error) {
+			actions = append(actions, "patch "+action.GetResource().Resource+"/"+action.(testing.PatchAction).GetName())
+			return true, nil, nil
+		})
+		kubeClient := fake.NewSimpleClientset()
+		kubeClient.Fake.PrependReactor("delete", "*", func(action testing.Action) (handled bool, ret runtime.Object, err error) {
+			actions = append(actions, "delete "+action.GetResource().Resource+"/"+action.(testing.DeleteAction).GetName())
+			return true, nil, nil
+		})
+
+		clientset := kubecli.NewMockKubevirtClient(ctrl)
+		clientset.EXPECT().DynamicClient().Return(dynamicClient).AnyTimes()
+		clientset.EXPECT().CoreV1().Return(kubeClient.CoreV1()).AnyTimes()
+		clientset.EXPECT().ExtensionsClient().Return(nil).AnyTimes()
+
+		r := &Reconciler{
+			kv:              &v1.KubeVirt{},
+			targetStrategy:  &install.Strategy{},
+			stores:          stores,
+			clientset:       clientset,
+			objectFinalizer: finalizer,
+			expectations: &util.Expectations{
+				ServiceAccount: controller.NewUIDTrackingControllerExpectations(controller.NewControllerExpectations()),
+			},
+		}
+
+		Expect(r.deleteObjectsNotInInstallStrategy()).To(Succeed())
+		Expect(actions).To(Equal([]string{"patch serviceaccounts/outdated", "delete serviceaccounts/outdated"}))
+	})
+})
+
 func instanceRemovedCondition() extv1.CustomResourceDefinitionCondition {
 	return extv1.CustomResourceDefinitionCondition{
 		Type:   extv1.Terminating,
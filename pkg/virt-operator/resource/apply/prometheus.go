@@ -21,6 +21,9 @@ func (r *Reconciler) createOrUpdateServiceMonitors() error {
 	}
 
 	for _, serviceMonitor := range r.targetStrategy.ServiceMonitors() {
+		if !r.shouldApplyObject("ServiceMonitor", serviceMonitor.Name) {
+			continue
+		}
 		if err := r.createOrUpdateServiceMonitor(serviceMonitor.DeepCopy()); err != nil {
 			return err
 		}
@@ -56,7 +59,7 @@ func (r *Reconciler) createOrUpdateServiceMonitor(serviceMonitor *promv1.Service
 	}
 
 	modified := resourcemerge.BoolPtr(false)
-	resourcemerge.EnsureObjectMeta(modified, &cachedServiceMonitor.ObjectMeta, serviceMonitor.ObjectMeta)
+	ensureObjectMeta(modified, &cachedServiceMonitor.ObjectMeta, serviceMonitor.ObjectMeta)
 
 	// there was no change to metadata and the spec fields are equal
 	if !*modified && !endpointsModified {
@@ -79,6 +82,7 @@ func (r *Reconciler) createOrUpdateServiceMonitor(serviceMonitor *promv1.Service
 	if err != nil {
 		return fmt.Errorf("unable to patch serviceMonitor %+v: %v", serviceMonitor, err)
 	}
+	r.recordPatch("ServiceMonitor", serviceMonitor.Namespace, serviceMonitor.Name, generatePatchBytes(ops))
 
 	log.Log.V(2).Infof("serviceMonitor %v updated", serviceMonitor.GetName())
 
@@ -103,6 +107,9 @@ func (r *Reconciler) createOrUpdatePrometheusRules() error {
 	}
 
 	for _, prometheusRule := range r.targetStrategy.PrometheusRules() {
+		if !r.shouldApplyObject("PrometheusRule", prometheusRule.Name) {
+			continue
+		}
 		if err := r.createOrUpdatePrometheusRule(prometheusRule.DeepCopy()); err != nil {
 			return err
 		}
@@ -135,7 +142,7 @@ func (r *Reconciler) createOrUpdatePrometheusRule(prometheusRule *promv1.Prometh
 	modified := resourcemerge.BoolPtr(false)
 	existingCopy := cachedPrometheusRule.DeepCopy()
 
-	resourcemerge.EnsureObjectMeta(modified, &existingCopy.ObjectMeta, prometheusRule.ObjectMeta)
+	ensureObjectMeta(modified, &existingCopy.ObjectMeta, prometheusRule.ObjectMeta)
 
 	if !*modified && equality.Semantic.DeepEqual(cachedPrometheusRule.Spec, prometheusRule.Spec) {
 		log.Log.V(4).Infof("PrometheusRule %v is up-to-date", prometheusRule.GetName())
@@ -157,6 +164,7 @@ func (r *Reconciler) createOrUpdatePrometheusRule(prometheusRule *promv1.Prometh
 	if err != nil {
 		return fmt.Errorf("unable to patch PrometheusRule %+v: %v", prometheusRule, err)
 	}
+	r.recordPatch("PrometheusRule", prometheusRule.Namespace, prometheusRule.Name, generatePatchBytes(ops))
 
 	log.Log.V(2).Infof("PrometheusRule %v updated", prometheusRule.GetName())
 
@@ -65,7 +65,7 @@ func rbacCreateOrUpdate(r *Reconciler, required runtime.Object, imageTag, imageR
 	existingCopy := cachedRoleInterface.(runtime.Object).DeepCopyObject()
 	existingCopyMeta := getRbacMetaObject(existingCopy)
 
-	resourcemerge.EnsureObjectMeta(metaChanged, existingCopyMeta, *requiredMeta)
+	ensureObjectMeta(metaChanged, existingCopyMeta, *requiredMeta)
 	enforceAPIGroup(existingCopy, required)
 
 	specChanged := changeRbacExistingByRequired(existingCopy, required)
@@ -23,6 +23,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -30,6 +31,7 @@ import (
 	"github.com/blang/semver"
 	promv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
 	secv1 "github.com/openshift/api/security/v1"
+	"github.com/openshift/library-go/pkg/operator/resource/resourcemerge"
 
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
@@ -61,6 +63,8 @@ const (
 	replaceWebhooksValueTemplate = `{ "op": "replace", "path": "/webhooks", "value": %s }`
 
 	testGenerationJSONPatchTemplate = `{ "op": "test", "path": "/metadata/generation", "value": %d }`
+
+	patchAppliedReason = "PatchApplied"
 )
 
 func objectMatchesVersion(objectMeta *metav1.ObjectMeta, version, imageRegistry, id string, generation int64) bool {
@@ -69,11 +73,9 @@ func objectMatchesVersion(objectMeta *metav1.ObjectMeta, version, imageRegistry,
 	}
 
 	foundVersion, foundImageRegistry, foundID, _ := getInstallStrategyAnnotations(objectMeta)
-	foundGeneration, generationExists := objectMeta.Annotations[v1.KubeVirtGenerationAnnotation]
 	foundLabels := util.IsManagedByOperator(objectMeta.Labels)
-	sGeneration := strconv.FormatInt(generation, 10)
 
-	if generationExists && foundGeneration != sGeneration {
+	if !ObjectGenerationMatches(objectMeta, generation) {
 		return false
 	}
 
@@ -84,6 +86,59 @@ func objectMatchesVersion(objectMeta *metav1.ObjectMeta, version, imageRegistry,
 	return false
 }
 
+// GetObjectGeneration returns the KubeVirt CR generation stored on the object by the last reconcile.
+// exists is false if the object carries no generation annotation.
+func GetObjectGeneration(objectMeta *metav1.ObjectMeta) (generation int64, exists bool, err error) {
+	value, exists := objectMeta.Annotations[v1.KubeVirtGenerationAnnotation]
+	if !exists {
+		return 0, false, nil
+	}
+	generation, err = strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, true, fmt.Errorf("invalid %s annotation %q: %v", v1.KubeVirtGenerationAnnotation, value, err)
+	}
+	return generation, true, nil
+}
+
+// ObjectGenerationMatches returns false if the object was reconciled for another KubeVirt CR generation or carries
+// an invalid generation annotation. Objects without the annotation match any generation.
+func ObjectGenerationMatches(objectMeta *metav1.ObjectMeta, generation int64) bool {
+	foundGeneration, exists, err := GetObjectGeneration(objectMeta)
+	if !exists {
+		return true
+	}
+	return err == nil && foundGeneration == generation
+}
+
+// ensureObjectMeta merges the required metadata into the existing metadata like resourcemerge.EnsureObjectMeta. The
+// existing object is also reported as modified if it was reconciled for another KubeVirt CR generation than the one
+// stored on the required object.
+func ensureObjectMeta(modified *bool, existing *metav1.ObjectMeta, required metav1.ObjectMeta) {
+	if generation, exists, err := GetObjectGeneration(&required); exists && err == nil && !ObjectGenerationMatches(existing, generation) {
+		*modified = true
+	}
+	resourcemerge.EnsureObjectMeta(modified, existing, required)
+}
+
+// DetectVersionSkew returns the sorted, distinct install strategy versions the objects were reconciled for, and whether
+// they disagree, e.g. after a partially applied upgrade. Objects without a version annotation are ignored.
+func DetectVersionSkew(objects []metav1.Object) (versions []string, skew bool) {
+	versionSet := map[string]struct{}{}
+	for _, obj := range objects {
+		if version, exists := obj.GetAnnotations()[v1.InstallStrategyVersionAnnotation]; exists {
+			versionSet[version] = struct{}{}
+		}
+	}
+
+	versions = make([]string, 0, len(versionSet))
+	for version := range versionSet {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+
+	return versions, len(versions) > 1
+}
+
 func injectOperatorMetadata(kv *v1.KubeVirt, objectMeta *metav1.ObjectMeta, version string, imageRegistry string, id string, injectCustomizationMetadata bool) {
 	if objectMeta.Labels == nil {
 		objectMeta.Labels = make(map[string]string)
@@ -225,6 +280,35 @@ func generatePatchBytes(ops []string) []byte {
 	return controller.GeneratePatchBytes(ops)
 }
 
+// summarizePatch lists the operations of a JSON patch as "op path" pairs
+func summarizePatch(patch []byte) string {
+	var operations []struct {
+		Op   string `json:"op"`
+		Path string `json:"path"`
+	}
+	if err := json.Unmarshal(patch, &operations); err != nil {
+		return fmt.Sprintf("unparsable patch: %v", err)
+	}
+
+	summary := make([]string, 0, len(operations))
+	for _, operation := range operations {
+		summary = append(summary, operation.Op+" "+operation.Path)
+	}
+	return strings.Join(summary, ", ")
+}
+
+// recordPatch records the applied patch as an event on the KubeVirt CR, if enabled by WithPatchEvents
+func (r *Reconciler) recordPatch(kind, namespace, name string, patch []byte) {
+	if !r.patchEvents {
+		return
+	}
+	object := name
+	if namespace != "" {
+		object = namespace + "/" + name
+	}
+	r.recorder.Eventf(r.kv, corev1.EventTypeNormal, patchAppliedReason, "patched %s %s: %s", kind, object, summarizePatch(patch))
+}
+
 func createLabelsAndAnnotationsPatch(objectMeta *metav1.ObjectMeta) ([]string, error) {
 	var ops []string
 	labelBytes, err := json.Marshal(objectMeta.Labels)
@@ -451,9 +535,109 @@ type Reconciler struct {
 	aggregatorclient install.APIServiceInterface
 	expectations     *util.Expectations
 	recorder         record.EventRecorder
+
+	// applyKinds limits the reconciliation to the given kinds if not empty
+	applyKinds map[string]struct{}
+	// applyObject limits the reconciliation to a single object if set, see ReconcileObject
+	applyObject *objectReference
+
+	resyncInterval time.Duration
+
+	// patchEvents records every applied patch as an event on the KubeVirt CR
+	patchEvents bool
+
+	// objectFinalizer is removed from objects before the reconciler deletes them
+	objectFinalizer string
+
+	reconcileMode ReconcileMode
+}
+
+// kinds which can be applied on their own, see WithApplyKinds
+var applyableKinds = []string{
+	"Namespace",
+	"CustomResourceDefinition",
+	"ServiceMonitor",
+	"PrometheusRule",
+	"ServiceAccount",
+	"ClusterRole",
+	"ClusterRoleBinding",
+	"Role",
+	"RoleBinding",
+	"SecurityContextConstraints",
+	"ConfigMap",
+	"Service",
+	"Deployment",
+	"DaemonSet",
+}
+
+func isApplyableKind(kind string) bool {
+	for _, applyableKind := range applyableKinds {
+		if kind == applyableKind {
+			return true
+		}
+	}
+	return false
+}
+
+type ReconcilerOption func(*Reconciler)
+
+// ReconcileMode selects how the reconciler brings drifted Services, Deployments and DaemonSets back in line with the
+// install strategy
+type ReconcileMode string
+
+const (
+	// ReconcileModePatch patches drifted objects, they are only recreated if an immutable field changed
+	ReconcileModePatch ReconcileMode = "Patch"
+	// ReconcileModeReplace deletes drifted objects, they are recreated from the install strategy on the next reconcile
+	ReconcileModeReplace ReconcileMode = "Replace"
+)
+
+// WithApplyKinds restricts the reconciler to creating and patching objects of the given kinds. Objects of all other
+// kinds are left untouched and no objects are deleted.
+func WithApplyKinds(kinds ...string) ReconcilerOption {
+	return func(r *Reconciler) {
+		if len(kinds) == 0 {
+			return
+		}
+		r.applyKinds = make(map[string]struct{}, len(kinds))
+		for _, kind := range kinds {
+			r.applyKinds[kind] = struct{}{}
+		}
+	}
+}
+
+// WithResyncInterval sets the interval of the full reconciles forced in addition to the ones triggered by events, see
+// ResyncInterval.
+func WithResyncInterval(interval time.Duration) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.resyncInterval = interval
+	}
+}
+
+// WithReconcileMode sets how drifted objects are reconciled, ReconcileModePatch is used by default.
+func WithReconcileMode(mode ReconcileMode) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.reconcileMode = mode
+	}
 }
 
-func NewReconciler(kv *v1.KubeVirt, targetStrategy *install.Strategy, stores util.Stores, clientset kubecli.KubevirtClient, aggregatorclient install.APIServiceInterface, expectations *util.Expectations, recorder record.EventRecorder) (*Reconciler, error) {
+// WithPatchEvents makes the reconciler record every patch it applies as an event on the KubeVirt CR, as an audit
+// trail of the changes done by the operator.
+func WithPatchEvents() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.patchEvents = true
+	}
+}
+
+// WithObjectFinalizer makes the reconciler remove the finalizer from objects before deleting them, so that objects
+// which are replaced or no longer part of the install strategy actually go away.
+func WithObjectFinalizer(finalizer string) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.objectFinalizer = finalizer
+	}
+}
+
+func NewReconciler(kv *v1.KubeVirt, targetStrategy *install.Strategy, stores util.Stores, clientset kubecli.KubevirtClient, aggregatorclient install.APIServiceInterface, expectations *util.Expectations, recorder record.EventRecorder, opts ...ReconcilerOption) (*Reconciler, error) {
 	kvKey, err := controller.KeyFunc(kv)
 	if err != nil {
 		return nil, err
@@ -469,7 +653,7 @@ func NewReconciler(kv *v1.KubeVirt, targetStrategy *install.Strategy, stores uti
 		return nil, err
 	}
 
-	return &Reconciler{
+	r := &Reconciler{
 		kv:               kv,
 		kvKey:            kvKey,
 		targetStrategy:   targetStrategy,
@@ -478,10 +662,71 @@ func NewReconciler(kv *v1.KubeVirt, targetStrategy *install.Strategy, stores uti
 		aggregatorclient: aggregatorclient,
 		expectations:     expectations,
 		recorder:         recorder,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	for kind := range r.applyKinds {
+		if !isApplyableKind(kind) {
+			return nil, fmt.Errorf("kind %s can not be applied on its own, supported kinds are %s", kind, strings.Join(applyableKinds, ", "))
+		}
+	}
+
+	if r.resyncInterval != 0 && r.resyncInterval < util.MinResyncInterval {
+		return nil, fmt.Errorf("resync interval %s is shorter than the minimum of %s", r.resyncInterval, util.MinResyncInterval)
+	}
+
+	switch r.reconcileMode {
+	case "":
+		r.reconcileMode = ReconcileModePatch
+	case ReconcileModePatch, ReconcileModeReplace:
+	default:
+		return nil, fmt.Errorf("invalid reconcile mode %q, supported modes are %s and %s", r.reconcileMode, ReconcileModePatch, ReconcileModeReplace)
+	}
+
+	return r, nil
+}
+
+// ResyncInterval returns the interval after which a full reconcile is forced even without events, or 0 if none is
+// forced
+func (r *Reconciler) ResyncInterval() time.Duration {
+	return r.resyncInterval
+}
+
+// shouldApply returns true if objects of the given kind are reconciled
+func (r *Reconciler) shouldApply(kind string) bool {
+	if len(r.applyKinds) == 0 {
+		return true
+	}
+	_, ok := r.applyKinds[kind]
+	return ok
+}
+
+// objectReference identifies an object of the install strategy
+type objectReference struct {
+	kind string
+	name string
+	// found is set once the object was seen by the reconciler
+	found bool
+}
+
+func (r *Reconciler) shouldApplyObject(kind, name string) bool {
+	if r.applyObject == nil {
+		return true
+	}
+	if r.applyObject.kind != kind || r.applyObject.name != name {
+		return false
+	}
+	r.applyObject.found = true
+	return true
 }
 
 func (r *Reconciler) Sync(queue workqueue.RateLimitingInterface) (bool, error) {
+	if len(r.applyKinds) > 0 {
+		return r.syncKinds()
+	}
+
 	// Avoid log spam by logging this issue once early instead of for once each object created
 	if !util.IsValidLabel(r.kv.Spec.ProductVersion) {
 		log.Log.Errorf("invalid kubevirt.spec.productVersion: labels must be 63 characters or less, begin and end with alphanumeric characters, and contain only dot, hyphen or underscore")
@@ -520,6 +765,12 @@ func (r *Reconciler) Sync(queue workqueue.RateLimitingInterface) (bool, error) {
 
 	// -------- CREATE AND ROLE OUT UPDATED OBJECTS --------
 
+	// namespaces have to exist before any object is created in them
+	err := r.createOrUpdateNamespaces()
+	if err != nil {
+		return false, err
+	}
+
 	// creates a blocking webhook for any new CRDs that don't exist previously.
 	// this webhook is removed once the new apiserver is online.
 	if !apiDeploymentsRolledOver {
@@ -535,7 +786,7 @@ func (r *Reconciler) Sync(queue workqueue.RateLimitingInterface) (bool, error) {
 	}
 
 	// create/update CRDs
-	err := r.createOrUpdateCrds()
+	err = r.createOrUpdateCrds()
 	if err != nil {
 		return false, err
 	}
@@ -572,6 +823,12 @@ func (r *Reconciler) Sync(queue workqueue.RateLimitingInterface) (bool, error) {
 		return false, err
 	}
 
+	// create/update ConfigMaps
+	err = r.createOrUpdateConfigMaps()
+	if err != nil {
+		return false, err
+	}
+
 	// create/update Services
 	pending, err := r.createOrUpdateServices()
 	if err != nil {
@@ -636,6 +893,117 @@ func (r *Reconciler) Sync(queue workqueue.RateLimitingInterface) (bool, error) {
 	return true, nil
 }
 
+// syncKinds creates and updates only the objects of the kinds selected by WithApplyKinds.
+// Rollover ordering, certificates and the clean up of old objects are skipped.
+func (r *Reconciler) syncKinds() (bool, error) {
+	if r.shouldApply("Namespace") {
+		if err := r.createOrUpdateNamespaces(); err != nil {
+			return false, err
+		}
+	}
+
+	if r.shouldApply("CustomResourceDefinition") {
+		if err := r.createOrUpdateCrds(); err != nil {
+			return false, err
+		}
+	}
+
+	if r.shouldApply("ServiceMonitor") {
+		if err := r.createOrUpdateServiceMonitors(); err != nil {
+			return false, err
+		}
+	}
+
+	if r.shouldApply("PrometheusRule") {
+		if err := r.createOrUpdatePrometheusRules(); err != nil {
+			return false, err
+		}
+	}
+
+	// RBAC kinds are filtered individually
+	if err := r.createOrUpdateRbac(); err != nil {
+		return false, err
+	}
+
+	if r.shouldApply("SecurityContextConstraints") {
+		if err := r.createOrUpdateSCC(); err != nil {
+			return false, err
+		}
+	}
+
+	if r.shouldApply("ConfigMap") {
+		if err := r.createOrUpdateConfigMaps(); err != nil {
+			return false, err
+		}
+	}
+
+	if r.shouldApply("Service") {
+		pending, err := r.createOrUpdateServices()
+		if pending || err != nil {
+			return false, err
+		}
+	}
+
+	if r.shouldApply("Deployment") {
+		deployments := append(r.targetStrategy.ApiDeployments(), r.targetStrategy.ControllerDeployments()...)
+		if r.exportProxyEnabled() {
+			deployments = append(deployments, r.targetStrategy.ExportProxyDeployments()...)
+		}
+		for _, deployment := range deployments {
+			if !r.shouldApplyObject("Deployment", deployment.Name) {
+				continue
+			}
+			if _, err := r.syncDeployment(deployment); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	if r.shouldApply("DaemonSet") {
+		for _, daemonSet := range r.targetStrategy.DaemonSets() {
+			if !r.shouldApplyObject("DaemonSet", daemonSet.Name) {
+				continue
+			}
+			finished, err := r.syncDaemonSet(daemonSet)
+			if !finished || err != nil {
+				return false, err
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// ReconcileObject creates or updates only the object of the target strategy with the given kind and name. It is
+// compared against the cached object and subject to the same conditions like during a full reconcile, all other objects
+// are left untouched. The returned bool is false if the object is not done yet, like a DaemonSet which is still rolling
+// out.
+func (r *Reconciler) ReconcileObject(kind, name string) (bool, error) {
+	if !isApplyableKind(kind) {
+		return false, fmt.Errorf("kind %s can not be reconciled on its own", kind)
+	}
+	if kind == "ConfigMap" && isCAConfigMap(name) {
+		return false, fmt.Errorf("ConfigMap %s is reconciled together with the certificates and can not be reconciled on its own", name)
+	}
+
+	applyKinds := r.applyKinds
+	r.applyKinds = map[string]struct{}{kind: {}}
+	r.applyObject = &objectReference{kind: kind, name: name}
+	defer func() {
+		r.applyKinds = applyKinds
+		r.applyObject = nil
+	}()
+
+	finished, err := r.syncKinds()
+	if err != nil {
+		return false, err
+	}
+	if !r.applyObject.found {
+		return false, fmt.Errorf("%s %s does not exist in the install strategy or is disabled", kind, name)
+	}
+	return finished, nil
+}
+
 func (r *Reconciler) createOrRollBackSystem(apiDeploymentsRolledOver bool) (bool, error) {
 	// CREATE/ROLLBACK PATH IS
 	// 1. apiserver - ensures validation of objects occur before allowing any control plane to act on them.
@@ -713,6 +1081,9 @@ func (r *Reconciler) deleteDeployment(deployment *appsv1.Deployment) error {
 	if err != nil {
 		return err
 	}
+	if err := r.releaseObject(deployment); err != nil {
+		return err
+	}
 	r.expectations.Deployment.AddExpectedDeletion(r.kvKey, key)
 	if err := r.clientset.AppsV1().Deployments(deployment.Namespace).Delete(context.Background(), deployment.Name, metav1.DeleteOptions{}); err != nil {
 		r.expectations.Deployment.DeletionObserved(r.kvKey, key)
@@ -722,6 +1093,27 @@ func (r *Reconciler) deleteDeployment(deployment *appsv1.Deployment) error {
 	return nil
 }
 
+func (r *Reconciler) deleteDaemonSet(daemonSet *appsv1.DaemonSet) error {
+	if daemonSet.DeletionTimestamp != nil {
+		return nil
+	}
+
+	key, err := controller.KeyFunc(daemonSet)
+	if err != nil {
+		return err
+	}
+	if err := r.releaseObject(daemonSet); err != nil {
+		return err
+	}
+	r.expectations.DaemonSet.AddExpectedDeletion(r.kvKey, key)
+	if err := r.clientset.AppsV1().DaemonSets(daemonSet.Namespace).Delete(context.Background(), daemonSet.Name, metav1.DeleteOptions{}); err != nil {
+		r.expectations.DaemonSet.DeletionObserved(r.kvKey, key)
+		return err
+	}
+
+	return nil
+}
+
 func (r *Reconciler) deleteObjectsNotInInstallStrategy() error {
 	gracePeriod := int64(0)
 	deleteOptions := metav1.DeleteOptions{
@@ -757,6 +1149,9 @@ func (r *Reconciler) deleteObjectsNotInInstallStrategy() error {
 			}
 			if !found {
 				if key, err := controller.KeyFunc(webhook); err == nil {
+					if err := r.releaseObject(webhook); err != nil {
+						return err
+					}
 					r.expectations.ValidationWebhook.AddExpectedDeletion(r.kvKey, key)
 					err := r.clientset.AdmissionregistrationV1().ValidatingWebhookConfigurations().Delete(context.Background(), webhook.Name, deleteOptions)
 					if err != nil {
@@ -789,6 +1184,9 @@ func (r *Reconciler) deleteObjectsNotInInstallStrategy() error {
 			}
 			if !found {
 				if key, err := controller.KeyFunc(webhook); err == nil {
+					if err := r.releaseObject(webhook); err != nil {
+						return err
+					}
 					r.expectations.MutatingWebhook.AddExpectedDeletion(r.kvKey, key)
 					err := r.clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Delete(context.Background(), webhook.Name, deleteOptions)
 					if err != nil {
@@ -821,6 +1219,9 @@ func (r *Reconciler) deleteObjectsNotInInstallStrategy() error {
 			}
 			if !found {
 				if key, err := controller.KeyFunc(apiService); err == nil {
+					if err := r.releaseObject(apiService); err != nil {
+						return err
+					}
 					r.expectations.APIService.AddExpectedDeletion(r.kvKey, key)
 					err := r.aggregatorclient.Delete(context.Background(), apiService.Name, deleteOptions)
 					if err != nil {
@@ -853,6 +1254,9 @@ func (r *Reconciler) deleteObjectsNotInInstallStrategy() error {
 			}
 			if !found {
 				if key, err := controller.KeyFunc(secret); err == nil {
+					if err := r.releaseObject(secret); err != nil {
+						return err
+					}
 					r.expectations.Secrets.AddExpectedDeletion(r.kvKey, key)
 					err := r.clientset.CoreV1().Secrets(secret.Namespace).Delete(context.Background(), secret.Name, deleteOptions)
 					if err != nil {
@@ -878,6 +1282,9 @@ func (r *Reconciler) deleteObjectsNotInInstallStrategy() error {
 			}
 			if !found {
 				if key, err := controller.KeyFunc(configMap); err == nil {
+					if err := r.releaseObject(configMap); err != nil {
+						return err
+					}
 					r.expectations.ConfigMap.AddExpectedDeletion(r.kvKey, key)
 					err := r.clientset.CoreV1().ConfigMaps(configMap.Namespace).Delete(context.Background(), configMap.Name, deleteOptions)
 					if err != nil {
@@ -928,6 +1335,9 @@ func (r *Reconciler) deleteObjectsNotInInstallStrategy() error {
 			}
 			if !found {
 				if key, err := controller.KeyFunc(ds); err == nil {
+					if err := r.releaseObject(ds); err != nil {
+						return err
+					}
 					r.expectations.DaemonSet.AddExpectedDeletion(r.kvKey, key)
 					err := r.clientset.AppsV1().DaemonSets(ds.Namespace).Delete(context.Background(), ds.Name, deleteOptions)
 					if err != nil {
@@ -953,6 +1363,9 @@ func (r *Reconciler) deleteObjectsNotInInstallStrategy() error {
 			}
 			if !found {
 				if key, err := controller.KeyFunc(deployment); err == nil {
+					if err := r.releaseObject(deployment); err != nil {
+						return err
+					}
 					r.expectations.Deployment.AddExpectedDeletion(r.kvKey, key)
 					err := r.clientset.AppsV1().Deployments(deployment.Namespace).Delete(context.Background(), deployment.Name, deleteOptions)
 					if err != nil {
@@ -978,6 +1391,9 @@ func (r *Reconciler) deleteObjectsNotInInstallStrategy() error {
 			}
 			if !found {
 				if key, err := controller.KeyFunc(svc); err == nil {
+					if err := r.releaseObject(svc); err != nil {
+						return err
+					}
 					r.expectations.Service.AddExpectedDeletion(r.kvKey, key)
 					err := r.clientset.CoreV1().Services(svc.Namespace).Delete(context.Background(), svc.Name, deleteOptions)
 					if err != nil {
@@ -1003,6 +1419,9 @@ func (r *Reconciler) deleteObjectsNotInInstallStrategy() error {
 			}
 			if !found {
 				if key, err := controller.KeyFunc(crb); err == nil {
+					if err := r.releaseObject(crb); err != nil {
+						return err
+					}
 					r.expectations.ClusterRoleBinding.AddExpectedDeletion(r.kvKey, key)
 					err := r.clientset.RbacV1().ClusterRoleBindings().Delete(context.Background(), crb.Name, deleteOptions)
 					if err != nil {
@@ -1028,6 +1447,9 @@ func (r *Reconciler) deleteObjectsNotInInstallStrategy() error {
 			}
 			if !found {
 				if key, err := controller.KeyFunc(cr); err == nil {
+					if err := r.releaseObject(cr); err != nil {
+						return err
+					}
 					r.expectations.ClusterRole.AddExpectedDeletion(r.kvKey, key)
 					err := r.clientset.RbacV1().ClusterRoles().Delete(context.Background(), cr.Name, deleteOptions)
 					if err != nil {
@@ -1053,6 +1475,9 @@ func (r *Reconciler) deleteObjectsNotInInstallStrategy() error {
 			}
 			if !found {
 				if key, err := controller.KeyFunc(rb); err == nil {
+					if err := r.releaseObject(rb); err != nil {
+						return err
+					}
 					r.expectations.RoleBinding.AddExpectedDeletion(r.kvKey, key)
 					err := r.clientset.RbacV1().RoleBindings(rb.Namespace).Delete(context.Background(), rb.Name, deleteOptions)
 					if err != nil {
@@ -1078,6 +1503,9 @@ func (r *Reconciler) deleteObjectsNotInInstallStrategy() error {
 			}
 			if !found {
 				if key, err := controller.KeyFunc(role); err == nil {
+					if err := r.releaseObject(role); err != nil {
+						return err
+					}
 					r.expectations.Role.AddExpectedDeletion(r.kvKey, key)
 					err := r.clientset.RbacV1().Roles(role.Namespace).Delete(context.Background(), role.Name, deleteOptions)
 					if err != nil {
@@ -1103,6 +1531,9 @@ func (r *Reconciler) deleteObjectsNotInInstallStrategy() error {
 			}
 			if !found {
 				if key, err := controller.KeyFunc(sa); err == nil {
+					if err := r.releaseObject(sa); err != nil {
+						return err
+					}
 					r.expectations.ServiceAccount.AddExpectedDeletion(r.kvKey, key)
 					err := r.clientset.CoreV1().ServiceAccounts(sa.Namespace).Delete(context.Background(), sa.Name, deleteOptions)
 					if err != nil {
@@ -1134,6 +1565,9 @@ func (r *Reconciler) deleteObjectsNotInInstallStrategy() error {
 			}
 			if !found {
 				if key, err := controller.KeyFunc(scc); err == nil {
+					if err := r.releaseObject(scc); err != nil {
+						return err
+					}
 					r.expectations.SCC.AddExpectedDeletion(r.kvKey, key)
 					err := r.clientset.SecClient().SecurityContextConstraints().Delete(context.Background(), scc.Name, deleteOptions)
 					if err != nil {
@@ -1159,6 +1593,9 @@ func (r *Reconciler) deleteObjectsNotInInstallStrategy() error {
 			}
 			if !found {
 				if key, err := controller.KeyFunc(cachePromRule); err == nil {
+					if err := r.releaseObject(cachePromRule); err != nil {
+						return err
+					}
 					r.expectations.PrometheusRule.AddExpectedDeletion(r.kvKey, key)
 					err := r.clientset.PrometheusClient().
 						MonitoringV1().
@@ -1187,6 +1624,9 @@ func (r *Reconciler) deleteObjectsNotInInstallStrategy() error {
 			}
 			if !found {
 				if key, err := controller.KeyFunc(cacheServiceMonitor); err == nil {
+					if err := r.releaseObject(cacheServiceMonitor); err != nil {
+						return err
+					}
 					r.expectations.ServiceMonitor.AddExpectedDeletion(r.kvKey, key)
 					err := r.clientset.PrometheusClient().
 						MonitoringV1().
@@ -22,7 +22,21 @@ package apply
 import (
 	"bufio"
 	"bytes"
+	"context"
+	"time"
 
+	"github.com/golang/mock/gomock"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+
+	"kubevirt.io/client-go/kubecli"
+
+	"kubevirt.io/kubevirt/pkg/controller"
+	"kubevirt.io/kubevirt/pkg/virt-operator/resource/generate/components"
 	installstrategy "kubevirt.io/kubevirt/pkg/virt-operator/resource/generate/install"
 	marshalutil "kubevirt.io/kubevirt/tools/util"
 
@@ -133,6 +147,91 @@ var _ = Describe("Apply", func() {
 		)
 	})
 
+	Context("KubeVirt generation annotation", func() {
+
+		DescribeTable("should be read", func(annotations map[string]string, expectedGeneration int64, expectedExists, expectErr bool) {
+			generation, exists, err := GetObjectGeneration(&metav1.ObjectMeta{Annotations: annotations})
+			Expect(exists).To(Equal(expectedExists))
+			Expect(generation).To(Equal(expectedGeneration))
+			if expectErr {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).ToNot(HaveOccurred())
+			}
+		},
+			Entry("when present", map[string]string{v1.KubeVirtGenerationAnnotation: "3"}, int64(3), true, false),
+			Entry("when absent", map[string]string{}, int64(0), false, false),
+			Entry("without annotations", nil, int64(0), false, false),
+			Entry("with an error when invalid", map[string]string{v1.KubeVirtGenerationAnnotation: "three"}, int64(0), true, true),
+		)
+
+		DescribeTable("should be compared", func(annotations map[string]string, matches bool) {
+			objectMeta := &metav1.ObjectMeta{Annotations: annotations}
+			Expect(ObjectGenerationMatches(objectMeta, 3)).To(Equal(matches))
+		},
+			Entry("as matching when present and equal", map[string]string{v1.KubeVirtGenerationAnnotation: "3"}, true),
+			Entry("as matching when absent", map[string]string{}, true),
+			Entry("as mismatching when different", map[string]string{v1.KubeVirtGenerationAnnotation: "2"}, false),
+			Entry("as mismatching when invalid", map[string]string{v1.KubeVirtGenerationAnnotation: "three"}, false),
+		)
+
+		It("should make objects with a mismatching generation outdated", func() {
+			kv := &v1.KubeVirt{}
+			kv.Generation = 3
+			objectMeta := &metav1.ObjectMeta{}
+			injectOperatorMetadata(kv, objectMeta, Version, Registry, Id, true)
+			Expect(objectMatchesVersion(objectMeta, Version, Registry, Id, 3)).To(BeTrue())
+			Expect(objectMatchesVersion(objectMeta, Version, Registry, Id, 4)).To(BeFalse())
+		})
+
+		DescribeTable("should report existing metadata as modified", func(annotations map[string]string, expectedModified bool) {
+			kv := &v1.KubeVirt{}
+			kv.Generation = 3
+			required := metav1.ObjectMeta{}
+			injectOperatorMetadata(kv, &required, Version, Registry, Id, true)
+			existing := required.DeepCopy()
+			for key, value := range annotations {
+				existing.Annotations[key] = value
+			}
+
+			modified := false
+			ensureObjectMeta(&modified, existing, required)
+			Expect(modified).To(Equal(expectedModified))
+			Expect(existing.Annotations).To(HaveKeyWithValue(v1.KubeVirtGenerationAnnotation, "3"))
+		},
+			Entry("not with the same generation", map[string]string{}, false),
+			Entry("with another generation", map[string]string{v1.KubeVirtGenerationAnnotation: "2"}, true),
+			Entry("with an invalid generation", map[string]string{v1.KubeVirtGenerationAnnotation: "three"}, true),
+		)
+
+		It("should patch a deployment reconciled for another generation", func() {
+			coreclientset := fake.NewSimpleClientset()
+			clientset := kubecli.NewMockKubevirtClient(gomock.NewController(GinkgoT()))
+			clientset.EXPECT().AppsV1().Return(coreclientset.AppsV1()).AnyTimes()
+
+			kv := &v1.KubeVirt{ObjectMeta: metav1.ObjectMeta{Name: "kubevirt", Namespace: Namespace, Generation: 3}}
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "virt-controller", Namespace: Namespace},
+				Spec:       appsv1.DeploymentSpec{Replicas: pointer.Int32(2)},
+			}
+			imageTag, imageRegistry, id := getTargetVersionRegistryID(kv)
+			cached := deployment.DeepCopy()
+			injectOperatorMetadata(kv, &cached.ObjectMeta, imageTag, imageRegistry, id, true)
+			cached.Annotations[v1.KubeVirtGenerationAnnotation] = "2"
+			cached.Generation = 1
+			Expect(coreclientset.Tracker().Add(cached)).To(Succeed())
+
+			stores := util.Stores{DeploymentCache: cache.NewStore(cache.DeletionHandlingMetaNamespaceKeyFunc)}
+			Expect(stores.DeploymentCache.Add(cached)).To(Succeed())
+			r := &Reconciler{kv: kv, stores: stores, clientset: clientset}
+
+			_, err := r.syncDeployment(deployment)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(coreclientset.Actions()).To(HaveLen(1))
+			Expect(coreclientset.Actions()[0].GetVerb()).To(Equal("patch"))
+		})
+	})
+
 	Context("Injecting Metadata", func() {
 
 		It("should set expected values", func() {
@@ -521,4 +620,484 @@ var _ = Describe("Apply", func() {
 			Expect(podSpec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution).To(HaveLen(1))
 		})
 	})
+
+	Context("version skew", func() {
+
+		newObject := func(version string) metav1.Object {
+			obj := &corev1.ConfigMap{}
+			if version != "" {
+				obj.Annotations = map[string]string{v1.InstallStrategyVersionAnnotation: version}
+			}
+			return obj
+		}
+
+		It("should detect objects reconciled for different versions", func() {
+			versions, skew := DetectVersionSkew([]metav1.Object{newObject("v1.0.0"), newObject("v1.1.0"), newObject("v1.0.0")})
+			Expect(skew).To(BeTrue())
+			Expect(versions).To(Equal([]string{"v1.0.0", "v1.1.0"}))
+		})
+
+		It("should not report skew for a single version", func() {
+			versions, skew := DetectVersionSkew([]metav1.Object{newObject("v1.1.0"), newObject(""), newObject("v1.1.0")})
+			Expect(skew).To(BeFalse())
+			Expect(versions).To(Equal([]string{"v1.1.0"}))
+		})
+	})
+
+	Context("with a resync interval", func() {
+
+		var targetStrategy *install.Strategy
+
+		BeforeEach(func() {
+			var err error
+			targetStrategy, err = installstrategy.GenerateCurrentInstallStrategy(getConfig(Registry, Version), "", Namespace)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should expose the interval of the forced full reconciles", func() {
+			r, err := NewReconciler(&v1.KubeVirt{}, targetStrategy, util.Stores{}, nil, nil, nil, nil, WithResyncInterval(10*time.Minute))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.ResyncInterval()).To(Equal(10 * time.Minute))
+
+			r, err = NewReconciler(&v1.KubeVirt{}, targetStrategy, util.Stores{}, nil, nil, nil, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(r.ResyncInterval()).To(BeZero())
+		})
+
+		It("should reject an interval below the minimum", func() {
+			_, err := NewReconciler(&v1.KubeVirt{}, targetStrategy, util.Stores{}, nil, nil, nil, nil, WithResyncInterval(time.Second))
+			Expect(err).To(MatchError(ContainSubstring("shorter than the minimum")))
+		})
+	})
+
+	Context("with ApplyKinds", func() {
+
+		var ctrl *gomock.Controller
+		var clientset *kubecli.MockKubevirtClient
+		var coreclientset *fake.Clientset
+		var stores util.Stores
+		var expectations *util.Expectations
+		var kv *v1.KubeVirt
+
+		BeforeEach(func() {
+			ctrl = gomock.NewController(GinkgoT())
+			coreclientset = fake.NewSimpleClientset()
+
+			stores = util.Stores{}
+			stores.ServiceCache = cache.NewStore(cache.DeletionHandlingMetaNamespaceKeyFunc)
+
+			expectations = &util.Expectations{}
+			expectations.Service = controller.NewUIDTrackingControllerExpectations(controller.NewControllerExpectationsWithName("Service"))
+
+			clientset = kubecli.NewMockKubevirtClient(ctrl)
+			clientset.EXPECT().CoreV1().Return(coreclientset.CoreV1()).AnyTimes()
+
+			kv = &v1.KubeVirt{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "kubevirt",
+					Namespace: Namespace,
+				},
+			}
+		})
+
+		It("should only patch services", func() {
+			targetStrategy, err := installstrategy.GenerateCurrentInstallStrategy(getConfig(Registry, Version), "", Namespace)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(targetStrategy.Deployments()).ToNot(BeEmpty())
+			Expect(targetStrategy.DaemonSets()).ToNot(BeEmpty())
+
+			for _, service := range targetStrategy.Services() {
+				outdated := service.DeepCopy()
+				outdated.Labels = map[string]string{"outdated": "true"}
+				Expect(stores.ServiceCache.Add(outdated)).To(Succeed())
+			}
+
+			var patched []string
+			coreclientset.Fake.PrependReactor("*", "*", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+				Expect(action.GetVerb()).To(Equal("patch"))
+				Expect(action.GetResource().Resource).To(Equal("services"))
+				patched = append(patched, action.(testing.PatchAction).GetName())
+				return true, nil, nil
+			})
+
+			r, err := NewReconciler(kv, targetStrategy, stores, clientset, nil, expectations, nil, WithApplyKinds("Service"))
+			Expect(err).ToNot(HaveOccurred())
+
+			done, err := r.Sync(nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(done).To(BeTrue())
+			Expect(patched).To(HaveLen(len(targetStrategy.Services())))
+		})
+
+		Context("with a generated namespace", func() {
+
+			var targetStrategy *install.Strategy
+
+			BeforeEach(func() {
+				config := getConfig(Registry, Version)
+				config.AdditionalProperties[util.AdditionalPropertiesCreateNamespace] = "true"
+				var err error
+				targetStrategy, err = installstrategy.GenerateCurrentInstallStrategy(config, "", Namespace)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(targetStrategy.NamespaceObjects()).To(HaveLen(1))
+
+				stores.NamespaceCache = cache.NewStore(cache.MetaNamespaceKeyFunc)
+			})
+
+			It("should create the namespace if it does not exist", func() {
+				r, err := NewReconciler(kv, targetStrategy, stores, clientset, nil, expectations, nil, WithApplyKinds("Namespace"))
+				Expect(err).ToNot(HaveOccurred())
+
+				done, err := r.Sync(nil)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(done).To(BeTrue())
+
+				namespace, err := coreclientset.CoreV1().Namespaces().Get(context.Background(), Namespace, metav1.GetOptions{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(namespace.Labels).To(Equal(targetStrategy.NamespaceObjects()[0].Labels))
+			})
+
+			It("should only add missing labels to an existing namespace", func() {
+				existing := &corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   Namespace,
+						Labels: map[string]string{"custom": "label"},
+					},
+				}
+				Expect(stores.NamespaceCache.Add(existing)).To(Succeed())
+				Expect(coreclientset.Tracker().Add(existing)).To(Succeed())
+
+				r, err := NewReconciler(kv, targetStrategy, stores, clientset, nil, expectations, nil, WithApplyKinds("Namespace"))
+				Expect(err).ToNot(HaveOccurred())
+
+				done, err := r.Sync(nil)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(done).To(BeTrue())
+
+				namespace, err := coreclientset.CoreV1().Namespaces().Get(context.Background(), Namespace, metav1.GetOptions{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(namespace.Labels).To(HaveKeyWithValue("custom", "label"))
+				for key, value := range targetStrategy.NamespaceObjects()[0].Labels {
+					Expect(namespace.Labels).To(HaveKeyWithValue(key, value))
+				}
+
+				Expect(stores.NamespaceCache.Update(namespace)).To(Succeed())
+				coreclientset.ClearActions()
+				done, err = r.Sync(nil)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(done).To(BeTrue())
+				Expect(coreclientset.Actions()).To(BeEmpty())
+			})
+		})
+
+		It("should reject kinds which can not be applied on their own", func() {
+			targetStrategy, err := installstrategy.GenerateCurrentInstallStrategy(getConfig(Registry, Version), "", Namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = NewReconciler(kv, targetStrategy, stores, clientset, nil, expectations, nil, WithApplyKinds("APIService"))
+			Expect(err).To(MatchError(ContainSubstring("kind APIService can not be applied on its own")))
+		})
+	})
+
+	Context("ReconcileObject", func() {
+
+		var clientset *kubecli.MockKubevirtClient
+		var coreclientset *fake.Clientset
+		var stores util.Stores
+		var expectations *util.Expectations
+		var targetStrategy *install.Strategy
+		var kv *v1.KubeVirt
+
+		BeforeEach(func() {
+			coreclientset = fake.NewSimpleClientset()
+			clientset = kubecli.NewMockKubevirtClient(gomock.NewController(GinkgoT()))
+			clientset.EXPECT().CoreV1().Return(coreclientset.CoreV1()).AnyTimes()
+
+			var err error
+			targetStrategy, err = installstrategy.GenerateCurrentInstallStrategy(getConfig(Registry, Version), "", Namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			stores = util.Stores{}
+			stores.ServiceCache = cache.NewStore(cache.DeletionHandlingMetaNamespaceKeyFunc)
+			for _, service := range targetStrategy.Services() {
+				outdated := service.DeepCopy()
+				outdated.Labels = map[string]string{"outdated": "true"}
+				Expect(stores.ServiceCache.Add(outdated)).To(Succeed())
+			}
+
+			expectations = &util.Expectations{}
+			expectations.Service = controller.NewUIDTrackingControllerExpectations(controller.NewControllerExpectationsWithName("Service"))
+
+			kv = &v1.KubeVirt{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "kubevirt",
+					Namespace: Namespace,
+				},
+			}
+		})
+
+		It("should only patch the selected service", func() {
+			Expect(len(targetStrategy.Services())).To(BeNumerically(">", 1))
+			selected := targetStrategy.Services()[0].Name
+
+			var patched []string
+			coreclientset.Fake.PrependReactor("*", "*", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+				Expect(action.GetVerb()).To(Equal("patch"))
+				Expect(action.GetResource().Resource).To(Equal("services"))
+				patched = append(patched, action.(testing.PatchAction).GetName())
+				return true, nil, nil
+			})
+
+			r, err := NewReconciler(kv, targetStrategy, stores, clientset, nil, expectations, nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			finished, err := r.ReconcileObject("Service", selected)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(finished).To(BeTrue())
+			Expect(patched).To(Equal([]string{selected}))
+		})
+
+		It("should fail for objects which are not part of the install strategy", func() {
+			r, err := NewReconciler(kv, targetStrategy, stores, clientset, nil, expectations, nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = r.ReconcileObject("Service", "unknown")
+			Expect(err).To(MatchError("Service unknown does not exist in the install strategy or is disabled"))
+			_, err = r.ReconcileObject("APIService", "unknown")
+			Expect(err).To(MatchError(ContainSubstring("kind APIService can not be reconciled on its own")))
+		})
+
+		It("should fail for the CA config maps", func() {
+			r, err := NewReconciler(kv, targetStrategy, stores, clientset, nil, expectations, nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			for _, name := range []string{components.KubeVirtCASecretName, components.KubeVirtExportCASecretName} {
+				_, err = r.ReconcileObject("ConfigMap", name)
+				Expect(err).To(MatchError(ContainSubstring("ConfigMap %s is reconciled together with the certificates", name)))
+			}
+			Expect(coreclientset.Actions()).To(BeEmpty())
+		})
+
+		It("should not create the export proxy while it is disabled", func() {
+			r, err := NewReconciler(kv, targetStrategy, stores, clientset, nil, expectations, nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = r.ReconcileObject("Deployment", components.VirtExportProxyName)
+			Expect(err).To(MatchError(ContainSubstring("does not exist in the install strategy or is disabled")))
+			Expect(coreclientset.Actions()).To(BeEmpty())
+		})
+
+		It("should report a DaemonSet which is not done yet as unfinished", func() {
+			daemonSet := targetStrategy.DaemonSets()[0].DeepCopy()
+			daemonSet.Labels = map[string]string{"outdated": "true"}
+			stores.DaemonSetCache = cache.NewStore(cache.DeletionHandlingMetaNamespaceKeyFunc)
+			Expect(stores.DaemonSetCache.Add(daemonSet)).To(Succeed())
+			Expect(coreclientset.Tracker().Add(daemonSet)).To(Succeed())
+			expectations.DaemonSet = controller.NewUIDTrackingControllerExpectations(controller.NewControllerExpectationsWithName("DaemonSet"))
+			clientset.EXPECT().AppsV1().Return(coreclientset.AppsV1()).AnyTimes()
+
+			r, err := NewReconciler(kv, targetStrategy, stores, clientset, nil, expectations, nil, WithReconcileMode(ReconcileModeReplace))
+			Expect(err).ToNot(HaveOccurred())
+
+			finished, err := r.ReconcileObject("DaemonSet", daemonSet.Name)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(finished).To(BeFalse())
+			Expect(r.applyKinds).To(BeEmpty())
+		})
+	})
+
+	Context("with PatchEvents", func() {
+
+		var clientset *kubecli.MockKubevirtClient
+		var stores util.Stores
+		var recorder *record.FakeRecorder
+		var kv *v1.KubeVirt
+		var service *corev1.Service
+
+		BeforeEach(func() {
+			coreclientset := fake.NewSimpleClientset()
+			coreclientset.Fake.PrependReactor("patch", "services", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+				return true, nil, nil
+			})
+			clientset = kubecli.NewMockKubevirtClient(gomock.NewController(GinkgoT()))
+			clientset.EXPECT().CoreV1().Return(coreclientset.CoreV1()).AnyTimes()
+
+			kv = &v1.KubeVirt{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "kubevirt",
+					Namespace: Namespace,
+				},
+			}
+
+			service = &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "virt-api",
+					Namespace: Namespace,
+					Labels:    map[string]string{"app": "virt-api"},
+				},
+				Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP},
+			}
+			outdated := service.DeepCopy()
+			outdated.Labels = map[string]string{"outdated": "true"}
+
+			stores = util.Stores{}
+			stores.ServiceCache = cache.NewStore(cache.DeletionHandlingMetaNamespaceKeyFunc)
+			Expect(stores.ServiceCache.Add(outdated)).To(Succeed())
+
+			recorder = record.NewFakeRecorder(10)
+		})
+
+		newReconciler := func(opts ...ReconcilerOption) *Reconciler {
+			targetStrategy, err := installstrategy.GenerateCurrentInstallStrategy(getConfig(Registry, Version), "", Namespace)
+			Expect(err).ToNot(HaveOccurred())
+			r, err := NewReconciler(kv, targetStrategy, stores, clientset, nil, nil, recorder, opts...)
+			Expect(err).ToNot(HaveOccurred())
+			return r
+		}
+
+		It("should record an event when a service patch is applied", func() {
+			_, err := newReconciler(WithPatchEvents()).createOrUpdateService(service)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(recorder.Events).To(Receive(And(
+				ContainSubstring(patchAppliedReason),
+				ContainSubstring("patched Service "+Namespace+"/virt-api: "),
+				ContainSubstring("add /metadata/labels"),
+			)))
+		})
+
+		It("should not record events by default", func() {
+			_, err := newReconciler().createOrUpdateService(service)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(recorder.Events).ToNot(Receive())
+		})
+	})
+
+	Context("with ReconcileMode", func() {
+
+		var coreclientset *fake.Clientset
+		var clientset *kubecli.MockKubevirtClient
+		var stores util.Stores
+		var expectations *util.Expectations
+		var kv *v1.KubeVirt
+		var service *corev1.Service
+		var deployment *appsv1.Deployment
+
+		BeforeEach(func() {
+			coreclientset = fake.NewSimpleClientset()
+			coreclientset.Fake.PrependReactor("*", "services", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+				return true, service, nil
+			})
+			coreclientset.Fake.PrependReactor("*", "deployments", func(action testing.Action) (handled bool, obj runtime.Object, err error) {
+				return true, deployment, nil
+			})
+			clientset = kubecli.NewMockKubevirtClient(gomock.NewController(GinkgoT()))
+			clientset.EXPECT().CoreV1().Return(coreclientset.CoreV1()).AnyTimes()
+			clientset.EXPECT().AppsV1().Return(coreclientset.AppsV1()).AnyTimes()
+
+			kv = &v1.KubeVirt{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "kubevirt",
+					Namespace: Namespace,
+				},
+			}
+
+			// only the labels of the cached objects differ from the target
+			service = &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "virt-api",
+					Namespace: Namespace,
+					Labels:    map[string]string{"app": "virt-api"},
+				},
+				Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP},
+			}
+			outdatedService := service.DeepCopy()
+			outdatedService.Labels = map[string]string{"outdated": "true"}
+
+			deployment = &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "virt-controller",
+					Namespace: Namespace,
+					Labels:    map[string]string{"app": "virt-controller"},
+				},
+				Spec: appsv1.DeploymentSpec{Replicas: pointer.Int32(2)},
+			}
+			outdatedDeployment := deployment.DeepCopy()
+			outdatedDeployment.Labels = map[string]string{"outdated": "true"}
+
+			stores = util.Stores{}
+			stores.ServiceCache = cache.NewStore(cache.DeletionHandlingMetaNamespaceKeyFunc)
+			Expect(stores.ServiceCache.Add(outdatedService)).To(Succeed())
+			stores.DeploymentCache = cache.NewStore(cache.DeletionHandlingMetaNamespaceKeyFunc)
+			Expect(stores.DeploymentCache.Add(outdatedDeployment)).To(Succeed())
+
+			expectations = &util.Expectations{
+				Service:    controller.NewUIDTrackingControllerExpectations(controller.NewControllerExpectationsWithName("Service")),
+				Deployment: controller.NewUIDTrackingControllerExpectations(controller.NewControllerExpectationsWithName("Deployment")),
+			}
+		})
+
+		newReconciler := func(opts ...ReconcilerOption) *Reconciler {
+			targetStrategy, err := installstrategy.GenerateCurrentInstallStrategy(getConfig(Registry, Version), "", Namespace)
+			Expect(err).ToNot(HaveOccurred())
+			r, err := NewReconciler(kv, targetStrategy, stores, clientset, nil, expectations, record.NewFakeRecorder(10), opts...)
+			Expect(err).ToNot(HaveOccurred())
+			return r
+		}
+
+		verbs := func(resource string) []string {
+			var verbs []string
+			for _, action := range coreclientset.Actions() {
+				if action.GetResource().Resource == resource {
+					verbs = append(verbs, action.GetVerb())
+				}
+			}
+			return verbs
+		}
+
+		It("should patch a metadata-only change by default", func() {
+			r := newReconciler()
+			requeue, err := r.createOrUpdateService(service)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(requeue).To(BeFalse())
+			_, err = r.syncDeployment(deployment)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(verbs("services")).To(ConsistOf("patch"))
+			Expect(verbs("deployments")).To(ConsistOf("patch"))
+		})
+
+		It("should delete and replace on a metadata-only change in Replace mode", func() {
+			r := newReconciler(WithReconcileMode(ReconcileModeReplace))
+			requeue, err := r.createOrUpdateService(service)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(requeue).To(BeTrue())
+			_, err = r.syncDeployment(deployment)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(verbs("services")).To(ConsistOf("delete"))
+			Expect(verbs("deployments")).To(ConsistOf("delete"))
+		})
+
+		It("should not touch up-to-date objects in Replace mode", func() {
+			r := newReconciler(WithReconcileMode(ReconcileModeReplace))
+			version, imageRegistry, id := getTargetVersionRegistryID(kv)
+			cached := service.DeepCopy()
+			injectOperatorMetadata(kv, &cached.ObjectMeta, version, imageRegistry, id, true)
+			Expect(stores.ServiceCache.Update(cached)).To(Succeed())
+
+			requeue, err := r.createOrUpdateService(service)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(requeue).To(BeFalse())
+			Expect(verbs("services")).To(BeEmpty())
+		})
+
+		It("should reject an unknown mode", func() {
+			targetStrategy, err := installstrategy.GenerateCurrentInstallStrategy(getConfig(Registry, Version), "", Namespace)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = NewReconciler(kv, targetStrategy, stores, clientset, nil, expectations, nil, WithReconcileMode("Sometimes"))
+			Expect(err).To(MatchError(`invalid reconcile mode "Sometimes", supported modes are Patch and Replace`))
+		})
+	})
 })
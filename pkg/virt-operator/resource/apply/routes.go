@@ -66,7 +66,7 @@ func (r *Reconciler) syncRoute(route *routev1.Route, caBundle []byte) error {
 
 	cachedRoute = obj.(*routev1.Route).DeepCopy()
 	modified := resourcemerge.BoolPtr(false)
-	resourcemerge.EnsureObjectMeta(modified, &cachedRoute.ObjectMeta, route.ObjectMeta)
+	ensureObjectMeta(modified, &cachedRoute.ObjectMeta, route.ObjectMeta)
 	kindSame := equality.Semantic.DeepEqual(cachedRoute.Spec.To.Kind, route.Spec.To.Kind)
 	nameSame := equality.Semantic.DeepEqual(cachedRoute.Spec.To.Name, route.Spec.To.Name)
 	terminationSame := equality.Semantic.DeepEqual(cachedRoute.Spec.TLS.Termination, route.Spec.TLS.Termination)
@@ -91,6 +91,7 @@ func (r *Reconciler) syncRoute(route *routev1.Route, caBundle []byte) error {
 	if err != nil {
 		return fmt.Errorf("unable to patch route %+v: %v", route, err)
 	}
+	r.recordPatch("Route", route.Namespace, route.Name, generatePatchBytes(ops))
 	log.Log.V(4).Infof("route %v updated", route.GetName())
 
 	return nil
@@ -110,6 +111,9 @@ func (r *Reconciler) deleteRoute(route *routev1.Route) error {
 	if err != nil {
 		return err
 	}
+	if err := r.releaseObject(route); err != nil {
+		return err
+	}
 	r.expectations.Route.AddExpectedDeletion(r.kvKey, key)
 	if err := r.clientset.RouteClient().Routes(route.Namespace).Delete(context.Background(), route.Name, metav1.DeleteOptions{}); err != nil {
 		r.expectations.Route.DeletionObserved(r.kvKey, key)
@@ -24,6 +24,9 @@ func (r *Reconciler) createOrUpdateSCC() error {
 	version, imageRegistry, id := getTargetVersionRegistryID(r.kv)
 
 	for _, scc := range r.targetStrategy.SCCs() {
+		if !r.shouldApplyObject("SecurityContextConstraints", scc.Name) {
+			continue
+		}
 		var cachedSCC *secv1.SecurityContextConstraints
 		scc := scc.DeepCopy()
 		obj, exists, _ := r.stores.SCCCache.GetByKey(scc.Name)
@@ -0,0 +1,74 @@
+package components
+
+import (
+	"sort"
+
+	k8sv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "kubevirt.io/api/core/v1"
+)
+
+const StorageClassDefaultsConfigMapName = "kubevirt-storage-class-defaults"
+
+// NewStorageClassDefaultsConfigMap returns the config map holding the storage defaults used when provisioning disks,
+// or nil if there are no defaults to store
+func NewStorageClassDefaultsConfigMap(namespace string, defaults map[string]string) *k8sv1.ConfigMap {
+	if len(defaults) == 0 {
+		return nil
+	}
+
+	data := make(map[string]string, len(defaults))
+	for k, v := range defaults {
+		data[k] = v
+	}
+
+	return &k8sv1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigMap",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      StorageClassDefaultsConfigMapName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				v1.ManagedByLabel: v1.ManagedByLabelOperatorValue,
+			},
+		},
+		Data: data,
+	}
+}
+
+// NewBinaryDataConfigMaps returns a config map for every entry of binaryData, keyed by config map name, holding the
+// entry as binaryData. The config maps are sorted by name.
+func NewBinaryDataConfigMaps(namespace string, binaryData map[string]map[string][]byte) []*k8sv1.ConfigMap {
+	names := make([]string, 0, len(binaryData))
+	for name := range binaryData {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	configMaps := make([]*k8sv1.ConfigMap, 0, len(names))
+	for _, name := range names {
+		data := make(map[string][]byte, len(binaryData[name]))
+		for k, v := range binaryData[name] {
+			data[k] = append([]byte{}, v...)
+		}
+
+		configMaps = append(configMaps, &k8sv1.ConfigMap{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "ConfigMap",
+				APIVersion: "v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels: map[string]string{
+					v1.ManagedByLabel: v1.ManagedByLabelOperatorValue,
+				},
+			},
+			BinaryData: data,
+		})
+	}
+	return configMaps
+}
@@ -216,6 +216,53 @@ func NewVirtualMachineCrd() (*extv1.CustomResourceDefinition, error) {
 	return crd, nil
 }
 
+// AddExtendedPrinterColumns appends additional wide output columns to the VirtualMachineInstance and VirtualMachine CRDs.
+// Other CRDs are left untouched.
+func AddExtendedPrinterColumns(crd *extv1.CustomResourceDefinition) {
+	var columns []extv1.CustomResourceColumnDefinition
+	switch crd.Name {
+	case VIRTUALMACHINEINSTANCE:
+		columns = []extv1.CustomResourceColumnDefinition{
+			{Name: "Guest-OS", Type: "string", JSONPath: ".status.guestOSInfo.prettyName", Priority: 1},
+			{Name: "QOS-Class", Type: "string", JSONPath: ".status.qosClass", Priority: 1},
+			{Name: "Migration-Method", Type: "string", JSONPath: ".status.migrationMethod", Priority: 1},
+		}
+	case VIRTUALMACHINE:
+		columns = []extv1.CustomResourceColumnDefinition{
+			{Name: "Run-Strategy", Type: "string", JSONPath: ".spec.runStrategy", Priority: 1},
+			{Name: "Created", Type: "boolean", JSONPath: ".status.created", Priority: 1},
+		}
+	default:
+		return
+	}
+
+	for i := range crd.Spec.Versions {
+		crd.Spec.Versions[i].AdditionalPrinterColumns = append(crd.Spec.Versions[i].AdditionalPrinterColumns, columns...)
+	}
+}
+
+// AddCRDNames appends extra short names and categories to the CRD, skipping the ones it already has
+func AddCRDNames(crd *extv1.CustomResourceDefinition, shortNames, categories []string) {
+	crd.Spec.Names.ShortNames = appendMissing(crd.Spec.Names.ShortNames, shortNames)
+	crd.Spec.Names.Categories = appendMissing(crd.Spec.Names.Categories, categories)
+}
+
+func appendMissing(existing, values []string) []string {
+	for _, value := range values {
+		found := false
+		for _, e := range existing {
+			if e == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			existing = append(existing, value)
+		}
+	}
+	return existing
+}
+
 func NewPresetCrd() (*extv1.CustomResourceDefinition, error) {
 	crd := newBlankCrd()
 
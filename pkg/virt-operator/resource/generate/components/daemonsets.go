@@ -331,3 +331,40 @@ func NewHandlerDaemonSet(namespace, repository, imagePrefix, version, launcherVe
 	return daemonset, nil
 
 }
+
+// AddHandlerExtraVolumes adds the given volumes to the virt-handler pod and the mounts to the virt-handler container.
+// Volumes and mounts which would collide with the ones virt-handler requires, or with each other, are rejected.
+func AddHandlerExtraVolumes(handler *appsv1.DaemonSet, volumes []corev1.Volume, volumeMounts []corev1.VolumeMount) error {
+	pod := &handler.Spec.Template.Spec
+
+	existingVolumes := map[string]struct{}{}
+	for _, volume := range pod.Volumes {
+		existingVolumes[volume.Name] = struct{}{}
+	}
+	for _, volume := range volumes {
+		if _, exists := existingVolumes[volume.Name]; exists {
+			return fmt.Errorf("extra volume %s of virt-handler collides with an existing volume", volume.Name)
+		}
+		existingVolumes[volume.Name] = struct{}{}
+		pod.Volumes = append(pod.Volumes, volume)
+	}
+
+	for i := range pod.Containers {
+		container := &pod.Containers[i]
+		if container.Name != VirtHandlerName {
+			continue
+		}
+		existingMounts := map[string]struct{}{}
+		for _, mount := range container.VolumeMounts {
+			existingMounts[mount.MountPath] = struct{}{}
+		}
+		for _, mount := range volumeMounts {
+			if _, exists := existingMounts[mount.MountPath]; exists {
+				return fmt.Errorf("extra volume mount of virt-handler at %s collides with an existing mount", mount.MountPath)
+			}
+			existingMounts[mount.MountPath] = struct{}{}
+			container.VolumeMounts = append(container.VolumeMounts, mount)
+		}
+	}
+	return nil
+}
@@ -22,6 +22,7 @@ package components
 import (
 	"fmt"
 	"path"
+	"strconv"
 	"strings"
 
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -50,6 +51,11 @@ const (
 	kubernetesHostnameTopologyKey = "kubernetes.io/hostname"
 
 	portName = "--port"
+
+	PrometheusServiceName = "kubevirt-prometheus-metrics"
+
+	// VirtApiMetricsTLSPort is the port virt-api serves metrics on when metrics TLS is enabled
+	VirtApiMetricsTLSPort = 8444
 )
 
 func NewPrometheusService(namespace string) *corev1.Service {
@@ -60,7 +66,7 @@ func NewPrometheusService(namespace string) *corev1.Service {
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: namespace,
-			Name:      "kubevirt-prometheus-metrics",
+			Name:      PrometheusServiceName,
 			Labels: map[string]string{
 				virtv1.AppLabel:    "",
 				prometheusLabelKey: prometheusLabelValue,
@@ -151,6 +157,32 @@ func NewExportProxyService(namespace string) *corev1.Service {
 	}
 }
 
+// AddLoadBalancerAnnotations adds the given annotations to the service if it is of type LoadBalancer, e.g. to configure
+// the load balancer of the cloud provider. Existing annotations are overwritten.
+func AddLoadBalancerAnnotations(service *corev1.Service, annotations map[string]string) {
+	if service.Spec.Type != corev1.ServiceTypeLoadBalancer || len(annotations) == 0 {
+		return
+	}
+	if service.Annotations == nil {
+		service.Annotations = map[string]string{}
+	}
+	for key, value := range annotations {
+		service.Annotations[key] = value
+	}
+}
+
+// MaxClientIPSessionAffinitySeconds is the longest ClientIP session affinity timeout accepted by the API server
+const MaxClientIPSessionAffinitySeconds = 86400
+
+// SetClientIPSessionAffinity makes the service route all requests of a client to the same pod until the client was
+// idle for the given number of seconds
+func SetClientIPSessionAffinity(service *corev1.Service, timeoutSeconds int32) {
+	service.Spec.SessionAffinity = corev1.ServiceAffinityClientIP
+	service.Spec.SessionAffinityConfig = &corev1.SessionAffinityConfig{
+		ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: &timeoutSeconds},
+	}
+}
+
 func newPodTemplateSpec(podName, imageName, repository, version, productName, productVersion, productComponent, image string, pullPolicy corev1.PullPolicy, imagePullSecrets []corev1.LocalObjectReference, podAffinity *corev1.Affinity, envVars *[]corev1.EnvVar) (*corev1.PodTemplateSpec, error) {
 
 	if image == "" {
@@ -388,6 +420,21 @@ func NewApiServerDeployment(namespace, repository, imagePrefix, version, product
 	return deployment, nil
 }
 
+// EnableApiMetricsTLS moves the virt-api metrics endpoint to a dedicated port, served with the operator managed
+// metrics certificate. The port keeps its name, so the prometheus service follows it.
+func EnableApiMetricsTLS(deployment *appsv1.Deployment) {
+	pod := &deployment.Spec.Template.Spec
+	attachCertificateSecret(pod, VirtApiMetricsCertSecretName, "/etc/virt-api/metrics-certificates")
+
+	container := &pod.Containers[0]
+	container.Args = append(container.Args, "--metrics-port", strconv.Itoa(VirtApiMetricsTLSPort))
+	for i, port := range container.Ports {
+		if port.Name == "metrics" {
+			container.Ports[i].ContainerPort = VirtApiMetricsTLSPort
+		}
+	}
+}
+
 func NewControllerDeployment(namespace, repository, imagePrefix, controllerVersion, launcherVersion, exportServerVersion, productName, productVersion, productComponent, image, launcherImage, exporterImage string, pullPolicy corev1.PullPolicy, imagePullSecrets []corev1.LocalObjectReference, verbosity string, extraEnv map[string]string) (*appsv1.Deployment, error) {
 	podAntiAffinity := newPodAntiAffinity(kubevirtLabelKey, kubernetesHostnameTopologyKey, metav1.LabelSelectorOpIn, []string{VirtControllerName})
 	deploymentName := VirtControllerName
@@ -727,6 +774,69 @@ func AddVersionSeparatorPrefix(version string) string {
 	return version
 }
 
+// AddArchitectureSuffix appends the architecture to an image tag.
+// shasums already point to an architecture specific image and are returned unchanged
+func AddArchitectureSuffix(version, arch string) string {
+	if arch == "" || strings.HasPrefix(version, "sha256:") || strings.HasPrefix(version, "{{if") {
+		return version
+	}
+	return fmt.Sprintf("%s-%s", version, arch)
+}
+
+// InjectArchitectureAffinity restricts the pod to nodes of the given architecture
+func InjectArchitectureAffinity(podSpec *corev1.PodSpec, arch string) {
+	if arch == "" {
+		return
+	}
+
+	term := corev1.NodeSelectorTerm{
+		MatchExpressions: []corev1.NodeSelectorRequirement{
+			{
+				Key:      corev1.LabelArchStable,
+				Operator: corev1.NodeSelectorOpIn,
+				Values:   []string{arch},
+			},
+		},
+	}
+
+	if podSpec.Affinity == nil {
+		podSpec.Affinity = &corev1.Affinity{}
+	}
+	if podSpec.Affinity.NodeAffinity == nil {
+		podSpec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	nodeAffinity := podSpec.Affinity.NodeAffinity
+	if nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{}
+	}
+	nodeSelector := nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if len(nodeSelector.NodeSelectorTerms) == 0 {
+		nodeSelector.NodeSelectorTerms = []corev1.NodeSelectorTerm{term}
+		return
+	}
+	// terms are ORed, so the requirement has to be added to every one of them
+	for i := range nodeSelector.NodeSelectorTerms {
+		nodeSelector.NodeSelectorTerms[i].MatchExpressions = append(nodeSelector.NodeSelectorTerms[i].MatchExpressions, term.MatchExpressions...)
+	}
+}
+
+// InjectGracefulShutdown sets the preStop command of the first container and the termination grace period of the
+// pod, if given
+func InjectGracefulShutdown(podSpec *corev1.PodSpec, preStopCommand []string, terminationGracePeriodSeconds *int64) {
+	if len(preStopCommand) > 0 {
+		container := &podSpec.Containers[0]
+		if container.Lifecycle == nil {
+			container.Lifecycle = &corev1.Lifecycle{}
+		}
+		container.Lifecycle.PreStop = &corev1.LifecycleHandler{
+			Exec: &corev1.ExecAction{Command: preStopCommand},
+		}
+	}
+	if terminationGracePeriodSeconds != nil {
+		podSpec.TerminationGracePeriodSeconds = pointer.Int64(*terminationGracePeriodSeconds)
+	}
+}
+
 func NewPodDisruptionBudgetForDeployment(deployment *appsv1.Deployment) *policyv1.PodDisruptionBudget {
 	pdbName := deployment.Name + "-pdb"
 	minAvailable := intstr.FromInt(1)
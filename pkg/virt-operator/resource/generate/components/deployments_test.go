@@ -13,4 +13,21 @@ var _ = Describe("Deployments", func() {
 		Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeClusterIP))
 		Expect(service.Spec.ClusterIP).To(Equal(corev1.ClusterIPNone))
 	})
+
+	Context("load balancer annotations", func() {
+		annotations := map[string]string{"service.beta.kubernetes.io/aws-load-balancer-internal": "true"}
+
+		It("should be added to LoadBalancer services", func() {
+			service := NewApiServerService("mynamespace")
+			service.Spec.Type = corev1.ServiceTypeLoadBalancer
+			AddLoadBalancerAnnotations(service, annotations)
+			Expect(service.Annotations).To(HaveKeyWithValue("service.beta.kubernetes.io/aws-load-balancer-internal", "true"))
+		})
+
+		It("should not be added to other services", func() {
+			service := NewApiServerService("mynamespace")
+			AddLoadBalancerAnnotations(service, annotations)
+			Expect(service.Annotations).To(BeEmpty())
+		})
+	})
 })
@@ -0,0 +1,25 @@
+package components
+
+import (
+	k8sv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "kubevirt.io/api/core/v1"
+)
+
+// NewNamespace returns the namespace to install KubeVirt into, for bootstrapping a fresh cluster from the strategy
+func NewNamespace(name string) *k8sv1.Namespace {
+	return &k8sv1.Namespace{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Namespace",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				v1.AppLabel:       "",
+				v1.ManagedByLabel: v1.ManagedByLabelOperatorValue,
+			},
+		},
+	}
+}
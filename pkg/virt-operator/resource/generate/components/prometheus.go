@@ -30,6 +30,13 @@ const (
 	durationFiveMinutes           = "5 minutes"
 )
 
+// PrometheusScrapeLabels returns the labels the ServiceMonitor selects the metrics service by
+func PrometheusScrapeLabels() map[string]string {
+	return map[string]string{
+		prometheusLabelKey: prometheusLabelValue,
+	}
+}
+
 func NewServiceMonitorCR(namespace string, monitorNamespace string, insecureSkipVerify bool) *v1.ServiceMonitor {
 	return &v1.ServiceMonitor{
 		TypeMeta: v12.TypeMeta{
@@ -47,9 +54,7 @@ func NewServiceMonitorCR(namespace string, monitorNamespace string, insecureSkip
 		},
 		Spec: v1.ServiceMonitorSpec{
 			Selector: v12.LabelSelector{
-				MatchLabels: map[string]string{
-					prometheusLabelKey: prometheusLabelValue,
-				},
+				MatchLabels: PrometheusScrapeLabels(),
 			},
 			NamespaceSelector: v1.NamespaceSelector{
 				MatchNames: []string{namespace},
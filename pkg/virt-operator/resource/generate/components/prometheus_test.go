@@ -17,6 +17,16 @@ var _ = Describe("Prometheus", func() {
 		os.Unsetenv(runbookURLTemplateEnv)
 	})
 
+	It("should scrape the metrics service by its labels", func() {
+		serviceMonitor := NewServiceMonitorCR("mynamespace", "monitoring", true)
+		service := NewPrometheusService("mynamespace")
+
+		Expect(serviceMonitor.Spec.Selector.MatchLabels).To(Equal(PrometheusScrapeLabels()))
+		for key, value := range serviceMonitor.Spec.Selector.MatchLabels {
+			Expect(service.Labels).To(HaveKeyWithValue(key, value))
+		}
+	})
+
 	It("should use the default runbook URL template when no ENV Variable is set", func() {
 		promRule := NewPrometheusRuleCR("mynamespace", true)
 
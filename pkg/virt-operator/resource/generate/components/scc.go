@@ -26,9 +26,9 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-func GetAllSCC(namespace string) []*secv1.SecurityContextConstraints {
+func GetAllSCC(namespace string, handlerHostNetwork bool) []*secv1.SecurityContextConstraints {
 	return []*secv1.SecurityContextConstraints{
-		NewKubeVirtHandlerSCC(namespace),
+		NewKubeVirtHandlerSCC(namespace, handlerHostNetwork),
 		NewKubeVirtControllerSCC(namespace),
 	}
 }
@@ -42,7 +42,9 @@ func newBlankSCC() *secv1.SecurityContextConstraints {
 	}
 }
 
-func NewKubeVirtHandlerSCC(namespace string) *secv1.SecurityContextConstraints {
+// NewKubeVirtHandlerSCC returns the SCC of virt-handler, hostNetwork has to be set if virt-handler runs in the network
+// namespace of the node
+func NewKubeVirtHandlerSCC(namespace string, hostNetwork bool) *secv1.SecurityContextConstraints {
 	scc := newBlankSCC()
 
 	scc.Name = "kubevirt-handler"
@@ -50,6 +52,7 @@ func NewKubeVirtHandlerSCC(namespace string) *secv1.SecurityContextConstraints {
 	scc.AllowHostPID = true
 	scc.AllowHostPorts = true
 	scc.AllowHostIPC = true
+	scc.AllowHostNetwork = hostNetwork
 	scc.RunAsUser = secv1.RunAsUserStrategyOptions{
 		Type: secv1.RunAsUserStrategyRunAsAny,
 	}
@@ -55,4 +55,15 @@ var _ = Describe("SCC", func() {
 		})
 	})
 
+	Context("virt-handler", func() {
+
+		It("should not allow the host network by default", func() {
+			Expect(NewKubeVirtHandlerSCC("test", false).AllowHostNetwork).To(BeFalse())
+		})
+
+		It("should allow the host network if virt-handler runs in it", func() {
+			Expect(NewKubeVirtHandlerSCC("test", true).AllowHostNetwork).To(BeTrue())
+		})
+	})
+
 })
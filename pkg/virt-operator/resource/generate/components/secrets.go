@@ -29,6 +29,7 @@ const (
 	VirtHandlerServerCertSecretName = "kubevirt-virt-handler-server-certs"
 	VirtOperatorCertSecretName      = "kubevirt-operator-certs"
 	VirtApiCertSecretName           = "kubevirt-virt-api-certs"
+	VirtApiMetricsCertSecretName    = "kubevirt-virt-api-metrics-certs"
 	VirtControllerCertSecretName    = "kubevirt-controller-certs"
 	VirtExportProxyCertSecretName   = "kubevirt-exportproxy-certs"
 	CABundleKey                     = "ca-bundle"
@@ -81,6 +82,23 @@ var populationStrategy = map[string]CertificateCreationCallback{
 		)
 		return keyPair.Cert, keyPair.Key
 	},
+	VirtApiMetricsCertSecretName: func(secret *k8sv1.Secret, caCert *tls.Certificate, duration time.Duration) (cert *x509.Certificate, key *ecdsa.PrivateKey) {
+		caKeyPair := &triple.KeyPair{
+			Key:  caCert.PrivateKey.(*ecdsa.PrivateKey),
+			Cert: caCert.Leaf,
+		}
+		keyPair, _ := triple.NewServerKeyPair(
+			caKeyPair,
+			fmt.Sprintf(LocalPodDNStemplateString, PrometheusServiceName, secret.Namespace),
+			PrometheusServiceName,
+			secret.Namespace,
+			CaClusterLocal,
+			nil,
+			nil,
+			duration,
+		)
+		return keyPair.Cert, keyPair.Key
+	},
 	VirtControllerCertSecretName: func(secret *k8sv1.Secret, caCert *tls.Certificate, duration time.Duration) (cert *x509.Certificate, key *ecdsa.PrivateKey) {
 		caKeyPair := &triple.KeyPair{
 			Key:  caCert.PrivateKey.(*ecdsa.PrivateKey),
@@ -320,6 +338,23 @@ func NewCertSecrets(installNamespace string, operatorNamespace string) []*k8sv1.
 	return secrets
 }
 
+func NewApiMetricsCertSecret(installNamespace string) *k8sv1.Secret {
+	return &k8sv1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      VirtApiMetricsCertSecretName,
+			Namespace: installNamespace,
+			Labels: map[string]string{
+				v1.ManagedByLabel: v1.ManagedByLabelOperatorValue,
+			},
+		},
+		Type: k8sv1.SecretTypeTLS,
+	}
+}
+
 // nextRotationDeadline returns a value for the threshold at which the
 // current certificate should be rotated, 80% of the expiration of the
 // certificate.
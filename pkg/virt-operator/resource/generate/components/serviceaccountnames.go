@@ -7,3 +7,11 @@ const (
 	HandlerServiceAccountName     = "kubevirt-handler"
 	OperatorServiceAccountName    = "kubevirt-operator"
 )
+
+// ComponentServiceAccountNames maps the components of the install strategy to their default service account name
+var ComponentServiceAccountNames = map[string]string{
+	VirtAPIName:         ApiServiceAccountName,
+	VirtControllerName:  ControllerServiceAccountName,
+	VirtExportProxyName: ExportProxyServiceAccountName,
+	VirtHandlerName:     HandlerServiceAccountName,
+}
@@ -0,0 +1,110 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package install
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "kubevirt.io/api/core/v1"
+
+	"kubevirt.io/kubevirt/pkg/virt-operator/resource/generate/components"
+	marshalutil "kubevirt.io/kubevirt/tools/util"
+)
+
+const (
+	// OfflineBundleImagesConfigMapName is the ConfigMap of an offline bundle listing the required images
+	OfflineBundleImagesConfigMapName = "kubevirt-offline-images"
+	// OfflineBundleImagesKey is the key of the image list in the images ConfigMap, one image per line
+	OfflineBundleImagesKey = "images"
+)
+
+// ExportOfflineBundle writes every object of the strategy once as YAML documents in the order they are applied,
+// followed by a ConfigMap listing every image the installation pulls, for mirroring them into an air-gapped registry.
+func ExportOfflineBundle(strategy *Strategy, w io.Writer) error {
+	objects, err := strategy.orderedObjects()
+	if err != nil {
+		return err
+	}
+	for _, o := range distinctObjects(objects) {
+		if err := marshalutil.MarshallObject(o.obj, w); err != nil {
+			return fmt.Errorf("failed to marshal %s %s: %v", o.kind, o.obj.GetName(), err)
+		}
+	}
+
+	return marshalutil.MarshallObject(newOfflineImagesConfigMap(strategy), w)
+}
+
+func newOfflineImagesConfigMap(strategy *Strategy) *corev1.ConfigMap {
+	namespace := ""
+	if len(strategy.deployments) > 0 {
+		namespace = strategy.deployments[0].Namespace
+	}
+
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigMap",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      OfflineBundleImagesConfigMapName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				v1.AppLabel: "",
+			},
+		},
+		Data: map[string]string{
+			OfflineBundleImagesKey: strings.Join(requiredImages(strategy), "\n"),
+		},
+	}
+}
+
+// requiredImages returns the sorted, distinct images of the strategy's containers and the images virt-controller
+// launches its pods with
+func requiredImages(strategy *Strategy) []string {
+	imageSet := map[string]struct{}{}
+	for _, image := range strategy.ImagesByComponent() {
+		imageSet[image] = struct{}{}
+	}
+	for _, deployment := range strategy.deployments {
+		if deployment.Name != components.VirtControllerName {
+			continue
+		}
+		for _, container := range deployment.Spec.Template.Spec.Containers {
+			for i := 0; i+1 < len(container.Args); i++ {
+				if container.Args[i] == "--launcher-image" || container.Args[i] == "--exporter-image" {
+					imageSet[container.Args[i+1]] = struct{}{}
+				}
+			}
+		}
+	}
+
+	images := make([]string, 0, len(imageSet))
+	for image := range imageSet {
+		images = append(images, image)
+	}
+	sort.Strings(images)
+	return images
+}
@@ -0,0 +1,109 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package install
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "kubevirt.io/api/core/v1"
+
+	"kubevirt.io/kubevirt/pkg/virt-operator/resource/generate/components"
+	"kubevirt.io/kubevirt/pkg/virt-operator/util"
+)
+
+var _ = Describe("Offline bundle", func() {
+
+	namespace := "fake-namespace"
+
+	It("should contain the objects followed by the image list", func() {
+		config := util.GetTargetConfigFromKV(&v1.KubeVirt{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+			},
+			Spec: v1.KubeVirtSpec{
+				ImageRegistry: "fake-registry",
+				ImageTag:      "v9.9.9",
+			},
+		})
+		strategy, err := GenerateCurrentInstallStrategy(config, "openshift-monitoring", namespace)
+		Expect(err).ToNot(HaveOccurred())
+
+		var b bytes.Buffer
+		Expect(ExportOfflineBundle(strategy, &b)).To(Succeed())
+
+		documents := strings.Split(strings.TrimPrefix(b.String(), "---\n"), "---\n")
+		Expect(documents).To(HaveLen(len(distinctObjects(strategy.objects())) + 1))
+		for _, name := range []string{components.KubeVirtCASecretName, components.KubeVirtExportCASecretName} {
+			caConfigMaps := 0
+			for _, document := range documents {
+				if strings.Contains(document, "kind: ConfigMap\n") && strings.Contains(document, "  name: "+name+"\n") {
+					caConfigMaps++
+				}
+			}
+			Expect(caConfigMaps).To(Equal(1), "the %s config map is added to the strategy twice", name)
+		}
+		Expect(b.String()).To(ContainSubstring("kind: Deployment"))
+		Expect(b.String()).To(ContainSubstring("kind: CustomResourceDefinition"))
+
+		configMap := &corev1.ConfigMap{}
+		Expect(yaml.Unmarshal([]byte(documents[len(documents)-1]), configMap)).To(Succeed())
+		Expect(configMap.Name).To(Equal(OfflineBundleImagesConfigMapName))
+		Expect(configMap.Namespace).To(Equal(namespace))
+		images := strings.Split(configMap.Data[OfflineBundleImagesKey], "\n")
+		Expect(images).To(ContainElements(
+			"fake-registry/virt-api:v9.9.9",
+			"fake-registry/virt-controller:v9.9.9",
+			"fake-registry/virt-handler:v9.9.9",
+			"fake-registry/virt-launcher:v9.9.9",
+			"fake-registry/virt-exportserver:v9.9.9",
+		))
+	})
+
+	It("should respect dependency hints", func() {
+		strategy := &Strategy{}
+		reader := &corev1.ServiceAccount{
+			TypeMeta: metav1.TypeMeta{Kind: "ServiceAccount", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   namespace,
+				Name:        "policy-reader",
+				Annotations: map[string]string{DependsOnAnnotation: "ConfigMap/policy"},
+			},
+		}
+		strategy.serviceAccounts = append(strategy.serviceAccounts, reader)
+		strategy.configMaps = append(strategy.configMaps, &corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "policy"},
+		})
+
+		var b bytes.Buffer
+		Expect(ExportOfflineBundle(strategy, &b)).To(Succeed())
+
+		bundle := b.String()
+		Expect(strings.Index(bundle, "name: policy-reader")).To(BeNumerically(">", strings.Index(bundle, "name: policy\n")))
+	})
+})
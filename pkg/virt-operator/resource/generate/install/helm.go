@@ -0,0 +1,209 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package install
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"kubevirt.io/kubevirt/pkg/virt-operator/resource/generate/components"
+	marshalutil "kubevirt.io/kubevirt/tools/util"
+)
+
+const (
+	helmTemplatesDir = "templates"
+	helmValuesFile   = "values.yaml"
+)
+
+// helmImage holds the values.yaml entry of a single container image
+type helmImage struct {
+	Repository string `json:"repository"`
+	Tag        string `json:"tag,omitempty"`
+	Digest     string `json:"digest,omitempty"`
+}
+
+// ExportHelmTemplates writes every object of the strategy once in apply order as Helm chart templates to <dir>/templates,
+// with the container images replaced by placeholders, and a values.yaml stub holding the images the strategy was
+// generated with
+func ExportHelmTemplates(strategy *Strategy, dir string) error {
+	templatesDir := filepath.Join(dir, helmTemplatesDir)
+	if err := os.MkdirAll(templatesDir, 0755); err != nil {
+		return err
+	}
+
+	objects, err := strategy.orderedObjects()
+	if err != nil {
+		return err
+	}
+
+	images := map[string]helmImage{}
+	for i, entry := range distinctObjects(objects) {
+		obj := entry.obj
+		var err error
+		switch o := obj.(type) {
+		case *appsv1.Deployment:
+			deployment := o.DeepCopy()
+			err = templateImages(&deployment.Spec.Template.Spec, images)
+			if err == nil && deployment.Name == components.VirtControllerName {
+				// virt-controller passes the images of the pods it creates as arguments
+				err = templateImageArgs(deployment.Spec.Template.Spec.Containers, images)
+			}
+			obj = deployment
+		case *appsv1.DaemonSet:
+			daemonSet := o.DeepCopy()
+			err = templateImages(&daemonSet.Spec.Template.Spec, images)
+			obj = daemonSet
+		}
+		if err != nil {
+			return err
+		}
+
+		var b bytes.Buffer
+		if err := marshalutil.MarshallObject(obj, &b); err != nil {
+			return err
+		}
+
+		// curly braces in descriptions and alert templates must reach the cluster as they are, the placeholders are
+		// only filled in afterwards, since they are long enough to get wrapped by the YAML encoder
+		content := strings.ReplaceAll(b.String(), "{{", `{{ "{{" }}`)
+		for key := range images {
+			content = strings.ReplaceAll(content, helmImageToken(key), helmImagePlaceholder(key, images[key]))
+		}
+
+		fileName := fmt.Sprintf("%03d-%s-%s.yaml", i, strings.ToLower(entry.kind), obj.GetName())
+		if err := os.WriteFile(filepath.Join(templatesDir, fileName), []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+
+	values, err := yaml.Marshal(map[string]interface{}{"images": images})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, helmValuesFile), values, 0644)
+}
+
+// templateImages replaces the images of all containers with tokens for the Helm
+// placeholders and records the original references in images, keyed by container name
+func templateImages(podSpec *corev1.PodSpec, images map[string]helmImage) error {
+	template := func(containers []corev1.Container) error {
+		for i := range containers {
+			container := &containers[i]
+			token, err := templateImage(helmValuesKey(container.Name), container.Image, images)
+			if err != nil {
+				return err
+			}
+			container.Image = token
+		}
+		return nil
+	}
+
+	if err := template(podSpec.InitContainers); err != nil {
+		return err
+	}
+	return template(podSpec.Containers)
+}
+
+// templateImageArgs replaces the images passed with --launcher-image and --exporter-image with tokens for the Helm
+// placeholders, keyed by the image name
+func templateImageArgs(containers []corev1.Container, images map[string]helmImage) error {
+	for _, container := range containers {
+		for i := 0; i+1 < len(container.Args); i++ {
+			if container.Args[i] != "--launcher-image" && container.Args[i] != "--exporter-image" {
+				continue
+			}
+			image := container.Args[i+1]
+			token, err := templateImage(helmValuesKey(path.Base(splitImage(image).Repository)), image, images)
+			if err != nil {
+				return err
+			}
+			container.Args[i+1] = token
+		}
+	}
+	return nil
+}
+
+// templateImage records the image under key and returns the token for its placeholder
+func templateImage(key string, reference string, images map[string]helmImage) (string, error) {
+	image := splitImage(reference)
+	if existing, exists := images[key]; exists && existing != image {
+		return "", fmt.Errorf("image %s conflicts with %s for %s", reference, joinImage(existing), key)
+	}
+	images[key] = image
+	return helmImageToken(key), nil
+}
+
+// helmImageToken is delimited on both sides so that keys sharing a prefix
+// are not mixed up
+func helmImageToken(key string) string {
+	return "__helm_image_" + key + "__"
+}
+
+func helmImagePlaceholder(key string, image helmImage) string {
+	placeholder := fmt.Sprintf("{{ .Values.images.%s.repository }}", key)
+	if image.Digest != "" {
+		placeholder += fmt.Sprintf("@{{ .Values.images.%s.digest }}", key)
+	} else if image.Tag != "" {
+		placeholder += fmt.Sprintf(":{{ .Values.images.%s.tag }}", key)
+	}
+	return placeholder
+}
+
+// splitImage splits an image reference into repository, tag and digest
+func splitImage(image string) helmImage {
+	if repository, digest, found := strings.Cut(image, "@"); found {
+		return helmImage{Repository: repository, Digest: digest}
+	}
+	// a colon before the last slash belongs to the registry port
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return helmImage{Repository: image[:i], Tag: image[i+1:]}
+	}
+	return helmImage{Repository: image}
+}
+
+func joinImage(image helmImage) string {
+	if image.Digest != "" {
+		return image.Repository + "@" + image.Digest
+	}
+	if image.Tag != "" {
+		return image.Repository + ":" + image.Tag
+	}
+	return image.Repository
+}
+
+// helmValuesKey turns a container name like virt-api into virtApi, since
+// dashes can't be used in Helm value paths
+func helmValuesKey(name string) string {
+	parts := strings.Split(name, "-")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
@@ -0,0 +1,163 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package install
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/ghodss/yaml"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v1 "kubevirt.io/api/core/v1"
+
+	"kubevirt.io/kubevirt/pkg/virt-operator/util"
+)
+
+var _ = Describe("Helm templates export", func() {
+
+	namespace := "fake-namespace"
+
+	config := util.GetTargetConfigFromKV(&v1.KubeVirt{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+		},
+		Spec: v1.KubeVirtSpec{
+			ImageRegistry: "fake-registry",
+			ImageTag:      "v9.9.9",
+		},
+	})
+
+	It("should replace images with placeholders and write the values stub", func() {
+		strategy, err := GenerateCurrentInstallStrategy(config, "openshift-monitoring", namespace)
+		Expect(err).ToNot(HaveOccurred())
+
+		dir := GinkgoT().TempDir()
+		Expect(ExportHelmTemplates(strategy, dir)).To(Succeed())
+
+		files, err := filepath.Glob(filepath.Join(dir, "templates", "*-deployment-virt-api.yaml"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(files).To(HaveLen(1))
+		template, err := os.ReadFile(files[0])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(template)).To(ContainSubstring("image: {{ .Values.images.virtApi.repository }}:{{ .Values.images.virtApi.tag }}"))
+		Expect(string(template)).ToNot(ContainSubstring("fake-registry"))
+
+		data, err := os.ReadFile(filepath.Join(dir, "values.yaml"))
+		Expect(err).ToNot(HaveOccurred())
+		values := map[string]map[string]helmImage{}
+		Expect(yaml.Unmarshal(data, &values)).To(Succeed())
+		Expect(values["images"]).To(HaveKeyWithValue("virtApi", helmImage{Repository: "fake-registry/virt-api", Tag: "v9.9.9"}))
+		Expect(values["images"]).To(HaveKey("virtController"))
+		Expect(values["images"]).To(HaveKey("virtHandler"))
+	})
+
+	It("should render back to the strategy images and keep literal curly braces", func() {
+		strategy, err := GenerateCurrentInstallStrategy(config, "openshift-monitoring", namespace)
+		Expect(err).ToNot(HaveOccurred())
+
+		dir := GinkgoT().TempDir()
+		Expect(ExportHelmTemplates(strategy, dir)).To(Succeed())
+
+		data, err := os.ReadFile(filepath.Join(dir, "values.yaml"))
+		Expect(err).ToNot(HaveOccurred())
+		values := map[string]interface{}{}
+		Expect(yaml.Unmarshal(data, &values)).To(Succeed())
+
+		files, err := filepath.Glob(filepath.Join(dir, "templates", "*.yaml"))
+		Expect(err).ToNot(HaveOccurred())
+		rendered := map[string]string{}
+		for _, file := range files {
+			content, err := os.ReadFile(file)
+			Expect(err).ToNot(HaveOccurred())
+			tmpl, err := template.New(filepath.Base(file)).Parse(string(content))
+			Expect(err).ToNot(HaveOccurred())
+			var b bytes.Buffer
+			Expect(tmpl.Execute(&b, map[string]interface{}{"Values": values})).To(Succeed())
+			rendered[filepath.Base(file)] = b.String()
+		}
+
+		var controller, rule string
+		for name, content := range rendered {
+			switch {
+			case strings.HasSuffix(name, "-deployment-virt-controller.yaml"):
+				controller = content
+			case strings.Contains(name, "-prometheusrule-"):
+				rule = content
+			}
+		}
+		Expect(controller).To(ContainSubstring("- fake-registry/virt-launcher:v9.9.9"))
+		Expect(controller).To(ContainSubstring("- fake-registry/virt-exportserver:v9.9.9"))
+		Expect(controller).To(ContainSubstring("image: fake-registry/virt-controller:v9.9.9"))
+		Expect(rule).To(ContainSubstring("{{ $labels."))
+	})
+
+	It("should write the templates in apply order", func() {
+		strategy, err := GenerateCurrentInstallStrategy(config, "openshift-monitoring", namespace)
+		Expect(err).ToNot(HaveOccurred())
+
+		dir := GinkgoT().TempDir()
+		Expect(ExportHelmTemplates(strategy, dir)).To(Succeed())
+
+		files, err := filepath.Glob(filepath.Join(dir, "templates", "*.yaml"))
+		Expect(err).ToNot(HaveOccurred())
+		ordered, err := strategy.orderedObjects()
+		Expect(err).ToNot(HaveOccurred())
+		ordered = distinctObjects(ordered)
+		Expect(files).To(HaveLen(len(ordered)))
+		for i, o := range ordered {
+			Expect(filepath.Base(files[i])).To(HaveSuffix("-%s.yaml", o.obj.GetName()))
+		}
+	})
+
+	It("should render every object exactly once", func() {
+		strategy, err := GenerateCurrentInstallStrategy(config, "openshift-monitoring", namespace)
+		Expect(err).ToNot(HaveOccurred())
+
+		dir := GinkgoT().TempDir()
+		Expect(ExportHelmTemplates(strategy, dir)).To(Succeed())
+
+		files, err := filepath.Glob(filepath.Join(dir, "templates", "*.yaml"))
+		Expect(err).ToNot(HaveOccurred())
+		rendered := map[string]int{}
+		for _, file := range files {
+			// the file names are made of the apply order, the kind and the name
+			_, name, _ := strings.Cut(filepath.Base(file), "-")
+			rendered[name]++
+		}
+
+		for _, o := range strategy.objects() {
+			Expect(rendered).To(HaveKeyWithValue(fmt.Sprintf("%s-%s.yaml", strings.ToLower(o.kind), o.obj.GetName()), 1))
+		}
+		Expect(files).To(HaveLen(len(distinctObjects(strategy.objects()))))
+	})
+
+	It("should split image references", func() {
+		Expect(splitImage("registry:5000/virt-api:v1")).To(Equal(helmImage{Repository: "registry:5000/virt-api", Tag: "v1"}))
+		Expect(splitImage("registry:5000/virt-api")).To(Equal(helmImage{Repository: "registry:5000/virt-api"}))
+		Expect(splitImage("registry/virt-api@sha256:abc")).To(Equal(helmImage{Repository: "registry/virt-api", Digest: "sha256:abc"}))
+	})
+})
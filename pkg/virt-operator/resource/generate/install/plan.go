@@ -0,0 +1,208 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package install
+
+import (
+	"encoding/json"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+const (
+	PlanActionCreate = "create"
+	PlanActionUpdate = "update"
+	PlanActionDelete = "delete"
+)
+
+// PlanAction is a single step to move the objects of one strategy to another one
+type PlanAction struct {
+	Action    string `json:"action"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// Patch is the JSON merge patch to apply for updates
+	Patch json.RawMessage `json:"patch,omitempty"`
+}
+
+func newPlanAction(action string, id objectIdentity) PlanAction {
+	return PlanAction{Action: action, Kind: id.Kind, Namespace: id.Namespace, Name: id.Name}
+}
+
+// GeneratePlan returns the actions needed to move from the current to the target strategy. Objects are matched by
+// kind, namespace and name and compared like in StrategyEqual. Creates and updates follow the order objects are
+// applied in, including dependency hints, deletes come last in reverse order.
+func GeneratePlan(current, target *Strategy) ([]PlanAction, error) {
+	currentObjects, err := current.canonicalObjects()
+	if err != nil {
+		return nil, err
+	}
+	targetObjects, err := target.canonicalObjects()
+	if err != nil {
+		return nil, err
+	}
+
+	targetList, err := target.orderedObjects()
+	if err != nil {
+		return nil, err
+	}
+
+	plan := []PlanAction{}
+	for _, o := range distinctObjects(targetList) {
+		id := o.identity()
+		currentObject, exists := currentObjects[id]
+		if !exists {
+			plan = append(plan, newPlanAction(PlanActionCreate, id))
+			continue
+		}
+
+		patch, err := mergePatch(currentObject, targetObjects[id])
+		if err != nil {
+			return nil, err
+		}
+		if string(patch) != "{}" {
+			update := newPlanAction(PlanActionUpdate, id)
+			update.Patch = patch
+			plan = append(plan, update)
+		}
+	}
+
+	currentList := distinctObjects(current.objects())
+	for i := len(currentList) - 1; i >= 0; i-- {
+		id := currentList[i].identity()
+		if _, exists := targetObjects[id]; !exists {
+			plan = append(plan, newPlanAction(PlanActionDelete, id))
+		}
+	}
+
+	return plan, nil
+}
+
+// ChangedObjectsSince returns a strategy with the objects of the target strategy which GeneratePlan creates or updates
+// when moving from the current strategy, so that only those have to be applied. Deletions are not part of the result.
+// The objects are shared with the target strategy.
+func ChangedObjectsSince(current, target *Strategy) (*Strategy, error) {
+	plan, err := GeneratePlan(current, target)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := map[objectIdentity]bool{}
+	for _, action := range plan {
+		if action.Action == PlanActionCreate || action.Action == PlanActionUpdate {
+			changed[objectIdentity{Kind: action.Kind, Namespace: action.Namespace, Name: action.Name}] = true
+		}
+	}
+
+	changedStrategy := &Strategy{}
+	for _, o := range target.objects() {
+		if changed[o.identity()] {
+			changedStrategy.addObject(o.obj)
+		}
+	}
+	return changedStrategy, nil
+}
+
+// StorageVersionChange is a CRD whose storage version differs between two strategies
+type StorageVersionChange struct {
+	CRD  string
+	From string
+	To   string
+}
+
+// StorageVersionChanges returns the CRDs present in both strategies whose storage version changes from the current to
+// the target strategy. Stored objects of these CRDs have to be migrated to the new storage version.
+func StorageVersionChanges(current, target *Strategy) []StorageVersionChange {
+	currentVersions := map[string]string{}
+	for _, crd := range current.crds {
+		currentVersions[crd.Name] = storageVersion(crd)
+	}
+
+	var changes []StorageVersionChange
+	for _, crd := range target.crds {
+		from, exists := currentVersions[crd.Name]
+		if to := storageVersion(crd); exists && from != to {
+			changes = append(changes, StorageVersionChange{CRD: crd.Name, From: from, To: to})
+		}
+	}
+	return changes
+}
+
+func storageVersion(crd *extv1.CustomResourceDefinition) string {
+	for _, version := range crd.Spec.Versions {
+		if version.Storage {
+			return version.Name
+		}
+	}
+	return ""
+}
+
+// GeneratePlanJSON returns the plan of GeneratePlan serialized as JSON
+func GeneratePlanJSON(current, target *Strategy) ([]byte, error) {
+	plan, err := GeneratePlan(current, target)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(plan)
+}
+
+// PlanSummary counts the objects of a reconcile by what happens to them
+type PlanSummary struct {
+	Created   int
+	Updated   int
+	Deleted   int
+	Unchanged int
+}
+
+// String returns the summary as one line for logging, like "created 2, updated 5, deleted 1, unchanged 40"
+func (s PlanSummary) String() string {
+	return fmt.Sprintf("created %d, updated %d, deleted %d, unchanged %d", s.Created, s.Updated, s.Deleted, s.Unchanged)
+}
+
+// SummarizePlan sorts the actions of a plan generated by GeneratePlan into buckets. The distinct objects of the target
+// strategy without an action are unchanged.
+func SummarizePlan(plan []PlanAction, target *Strategy) PlanSummary {
+	var summary PlanSummary
+	for _, action := range plan {
+		switch action.Action {
+		case PlanActionCreate:
+			summary.Created++
+		case PlanActionUpdate:
+			summary.Updated++
+		case PlanActionDelete:
+			summary.Deleted++
+		}
+	}
+	summary.Unchanged = len(distinctObjects(target.objects())) - summary.Created - summary.Updated
+	return summary
+}
+
+func mergePatch(current, target map[string]interface{}) ([]byte, error) {
+	currentBytes, err := json.Marshal(current)
+	if err != nil {
+		return nil, err
+	}
+	targetBytes, err := json.Marshal(target)
+	if err != nil {
+		return nil, err
+	}
+	return jsonpatch.CreateMergePatch(currentBytes, targetBytes)
+}
@@ -0,0 +1,249 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package install
+
+import (
+	"encoding/json"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	v1 "kubevirt.io/api/core/v1"
+
+	"kubevirt.io/kubevirt/pkg/virt-operator/util"
+)
+
+var _ = Describe("Install Strategy plan", func() {
+
+	namespace := "fake-namespace"
+
+	config := util.GetTargetConfigFromKV(&v1.KubeVirt{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+		},
+		Spec: v1.KubeVirtSpec{
+			ImageRegistry: "fake-registry",
+			ImageTag:      "v9.9.9",
+		},
+	})
+
+	var current, target *Strategy
+
+	BeforeEach(func() {
+		var err error
+		current, err = GenerateCurrentInstallStrategy(config, "openshift-monitoring", namespace)
+		Expect(err).ToNot(HaveOccurred())
+		target, err = GenerateCurrentInstallStrategy(config, "openshift-monitoring", namespace)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	generatePlan := func() []PlanAction {
+		planBytes, err := GeneratePlanJSON(current, target)
+		Expect(err).ToNot(HaveOccurred())
+		plan := []PlanAction{}
+		Expect(json.Unmarshal(planBytes, &plan)).To(Succeed())
+		return plan
+	}
+
+	It("should be empty for equal strategies", func() {
+		Expect(generatePlan()).To(BeEmpty())
+	})
+
+	It("should create objects only present in the target strategy", func() {
+		target.serviceAccounts = append(target.serviceAccounts, newSA(namespace, "extra"))
+
+		Expect(generatePlan()).To(ConsistOf(PlanAction{
+			Action:    PlanActionCreate,
+			Kind:      "ServiceAccount",
+			Namespace: namespace,
+			Name:      "extra",
+		}))
+	})
+
+	It("should delete objects only present in the current strategy", func() {
+		removed := target.serviceAccounts[0]
+		target.serviceAccounts = target.serviceAccounts[1:]
+
+		Expect(generatePlan()).To(ConsistOf(PlanAction{
+			Action:    PlanActionDelete,
+			Kind:      "ServiceAccount",
+			Namespace: removed.Namespace,
+			Name:      removed.Name,
+		}))
+	})
+
+	It("should update changed objects with a merge patch", func() {
+		deployment := target.deployments[0]
+		deployment.Spec.Template.Spec.Containers[0].Image = "other-registry/image:v1.0.0"
+
+		plan := generatePlan()
+		Expect(plan).To(HaveLen(1))
+		Expect(plan[0].Action).To(Equal(PlanActionUpdate))
+		Expect(plan[0].Kind).To(Equal("Deployment"))
+		Expect(plan[0].Name).To(Equal(deployment.Name))
+		Expect(string(plan[0].Patch)).To(ContainSubstring("other-registry/image:v1.0.0"))
+	})
+
+	It("should order deletes after creates and updates", func() {
+		target.serviceAccounts = append(target.serviceAccounts[1:], newSA(namespace, "extra"))
+
+		plan := generatePlan()
+		Expect(plan).To(HaveLen(2))
+		Expect(plan[0].Action).To(Equal(PlanActionCreate))
+		Expect(plan[1].Action).To(Equal(PlanActionDelete))
+	})
+
+	It("should create objects which are added to the strategy twice only once", func() {
+		current.configMaps = nil
+
+		plan := generatePlan()
+		Expect(plan).To(HaveLen(len(distinctObjects(target.objects())) - len(distinctObjects(current.objects()))))
+		for _, action := range plan {
+			Expect(action.Action).To(Equal(PlanActionCreate))
+			Expect(action.Kind).To(Equal("ConfigMap"))
+		}
+		Expect(SummarizePlan(plan, target).Unchanged).To(Equal(len(distinctObjects(current.objects()))))
+	})
+
+	Context("summary", func() {
+		It("should count every object in its bucket", func() {
+			total := len(distinctObjects(target.objects()))
+			Expect(total).To(BeNumerically("<", len(target.objects())), "the CA config maps are added twice")
+			Expect(SummarizePlan(generatePlan(), target).String()).To(Equal(fmt.Sprintf("created 0, updated 0, deleted 0, unchanged %d", total)))
+
+			target.serviceAccounts = append(target.serviceAccounts[1:], newSA(namespace, "extra"), newSA(namespace, "other"))
+			target.deployments[0].Spec.Template.Spec.Containers[0].Image = "other-registry/image:v1.0.0"
+
+			summary := SummarizePlan(generatePlan(), target)
+			Expect(summary).To(Equal(PlanSummary{Created: 2, Updated: 1, Deleted: 1, Unchanged: total - 2}))
+			Expect(summary.String()).To(Equal(fmt.Sprintf("created 2, updated 1, deleted 1, unchanged %d", total-2)))
+		})
+	})
+
+	Context("dependency hints", func() {
+		var policy *corev1.ConfigMap
+
+		BeforeEach(func() {
+			policy = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "policy"}}
+			target.configMaps = append(target.configMaps, policy)
+		})
+
+		indexOf := func(objects []runtime.Object, obj runtime.Object) int {
+			for i, o := range objects {
+				if o == obj {
+					return i
+				}
+			}
+			return -1
+		}
+
+		It("should order an object after its dependency", func() {
+			reader := newSA(namespace, "policy-reader")
+			reader.Annotations = map[string]string{DependsOnAnnotation: "ConfigMap/policy"}
+			target.serviceAccounts = append(target.serviceAccounts, reader)
+
+			objects, err := target.OrderedObjects()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(objects).To(HaveLen(len(target.objects())))
+			Expect(indexOf(objects, reader)).To(BeNumerically(">", indexOf(objects, policy)))
+			// objects without hints keep their order
+			Expect(indexOf(objects, target.serviceAccounts[0])).To(Equal(0))
+
+			plan := generatePlan()
+			Expect(plan).To(HaveLen(2))
+			Expect(plan[0].Name).To(Equal("policy"))
+			Expect(plan[1].Name).To(Equal("policy-reader"))
+		})
+
+		It("should ignore dependencies which are not part of the strategy", func() {
+			reader := newSA(namespace, "policy-reader")
+			reader.Annotations = map[string]string{DependsOnAnnotation: "ConfigMap/missing"}
+			target.serviceAccounts = append(target.serviceAccounts, reader)
+
+			objects, err := target.OrderedObjects()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(objects).To(ContainElement(reader))
+		})
+
+		It("should fail for circular dependencies", func() {
+			reader := newSA(namespace, "policy-reader")
+			reader.Annotations = map[string]string{DependsOnAnnotation: "ConfigMap/policy"}
+			target.serviceAccounts = append(target.serviceAccounts, reader)
+			policy.Annotations = map[string]string{DependsOnAnnotation: "ServiceAccount/policy-reader"}
+
+			_, err := target.OrderedObjects()
+			Expect(err).To(MatchError("ServiceAccount policy-reader is part of a dependency cycle"))
+		})
+	})
+
+	Context("changed objects", func() {
+		It("should be empty for equal strategies", func() {
+			changed, err := ChangedObjectsSince(current, target)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changed.objects()).To(BeEmpty())
+		})
+
+		It("should only contain created and updated objects", func() {
+			removed := target.configMaps[0]
+			target.configMaps = target.configMaps[1:]
+			extra := newSA(namespace, "extra")
+			target.serviceAccounts = append(target.serviceAccounts, extra)
+			deployment := target.deployments[0]
+			deployment.Spec.Template.Spec.Containers[0].Image = "other-registry/image:v1.0.0"
+
+			changed, err := ChangedObjectsSince(current, target)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changed.ServiceAccounts()).To(ConsistOf(extra))
+			Expect(changed.Deployments()).To(ConsistOf(deployment))
+			Expect(changed.ConfigMaps()).ToNot(ContainElement(removed))
+			Expect(changed.objects()).To(HaveLen(2))
+		})
+	})
+
+	Context("storage version changes", func() {
+		It("should be empty for equal strategies", func() {
+			Expect(StorageVersionChanges(current, target)).To(BeEmpty())
+		})
+
+		It("should report a CRD whose storage version changed", func() {
+			var crd *extv1.CustomResourceDefinition
+			for _, c := range target.crds {
+				if c.Name == "virtualmachineinstances.kubevirt.io" {
+					crd = c
+				}
+			}
+			Expect(crd).ToNot(BeNil())
+			for i := range crd.Spec.Versions {
+				crd.Spec.Versions[i].Storage = crd.Spec.Versions[i].Name == "v1alpha3"
+			}
+
+			Expect(StorageVersionChanges(current, target)).To(ConsistOf(StorageVersionChange{
+				CRD:  crd.Name,
+				From: "v1",
+				To:   "v1alpha3",
+			}))
+		})
+	})
+})
@@ -24,12 +24,20 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	// #nosec sha1 is used to calculate a checksum of config maps and not for cryptographic purposes
+	"crypto/sha1"
 	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
+	"reflect"
+	"regexp"
+	"sort"
 	"strings"
 
 	promv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
+	jsonpatch "github.com/evanphx/json-patch"
 	"github.com/ghodss/yaml"
 	"github.com/golang/glog"
 	routev1 "github.com/openshift/api/route/v1"
@@ -44,7 +52,9 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	k8coresv1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	apiregv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
 
@@ -52,6 +62,7 @@ import (
 	"kubevirt.io/client-go/kubecli"
 	"kubevirt.io/client-go/log"
 
+	"kubevirt.io/kubevirt/pkg/controller"
 	"kubevirt.io/kubevirt/pkg/virt-operator/resource/generate/components"
 	"kubevirt.io/kubevirt/pkg/virt-operator/resource/generate/rbac"
 	"kubevirt.io/kubevirt/pkg/virt-operator/util"
@@ -62,6 +73,17 @@ import (
 const customSCCPrivilegedAccountsType = "KubevirtCustomSCCRule"
 const ManifestsEncodingGzipBase64 = "gzip+base64"
 
+// ConfigMapChecksumAnnotation is set on the pod template of every Deployment which uses a ConfigMap of the strategy.
+// Its value changes with the content of those ConfigMaps, which makes the Deployment roll out new pods.
+const ConfigMapChecksumAnnotation = "kubevirt.io/configmap-checksum"
+
+// DependsOnAnnotation holds a comma separated list of "Kind/name" objects of the strategy which have to be applied
+// before the annotated object. Namespaced dependencies are looked up in the namespace of the annotated object.
+const DependsOnAnnotation = "kubevirt.io/depends-on"
+
+// maxInstallStrategyConfigMapSize is the maximum size of the data of a ConfigMap accepted by the API server
+const maxInstallStrategyConfigMapSize = 1024 * 1024
+
 //go:generate mockgen -source $GOFILE -imports "libvirt=libvirt.org/go/libvirt" -package=$GOPACKAGE -destination=generated_mock_$GOFILE
 
 type APIServiceInterface interface {
@@ -72,6 +94,8 @@ type APIServiceInterface interface {
 }
 
 type Strategy struct {
+	namespaces []*corev1.Namespace
+
 	serviceAccounts []*corev1.ServiceAccount
 
 	clusterRoles        []*rbacv1.ClusterRole
@@ -96,6 +120,12 @@ type Strategy struct {
 	routes                          []*routev1.Route
 }
 
+// NamespaceObjects returns the namespaces generated for bootstrapping a fresh install. They are created before all
+// other objects, but never deleted by the operator.
+func (ins *Strategy) NamespaceObjects() []*corev1.Namespace {
+	return ins.namespaces
+}
+
 func (ins *Strategy) ServiceAccounts() []*corev1.ServiceAccount {
 	return ins.serviceAccounts
 }
@@ -127,86 +157,1244 @@ func (ins *Strategy) Deployments() []*appsv1.Deployment {
 func (ins *Strategy) ApiDeployments() []*appsv1.Deployment {
 	var deployments []*appsv1.Deployment
 
-	for _, deployment := range ins.deployments {
-		if !strings.Contains(deployment.Name, "virt-api") {
-			continue
+	for _, deployment := range ins.deployments {
+		if !strings.Contains(deployment.Name, "virt-api") {
+			continue
+		}
+		deployments = append(deployments, deployment)
+	}
+
+	return deployments
+}
+
+func (ins *Strategy) ControllerDeployments() []*appsv1.Deployment {
+	var deployments []*appsv1.Deployment
+
+	for _, deployment := range ins.deployments {
+		if !strings.Contains(deployment.Name, "virt-controller") {
+			continue
+		}
+		deployments = append(deployments, deployment)
+
+	}
+
+	return deployments
+}
+
+func (ins *Strategy) ExportProxyDeployments() []*appsv1.Deployment {
+	var deployments []*appsv1.Deployment
+
+	for _, deployment := range ins.deployments {
+		if !strings.Contains(deployment.Name, "virt-exportproxy") {
+			continue
+		}
+		deployments = append(deployments, deployment)
+
+	}
+
+	return deployments
+}
+
+func (ins *Strategy) DaemonSets() []*appsv1.DaemonSet {
+	return ins.daemonSets
+}
+
+func (ins *Strategy) ValidatingWebhookConfigurations() []*admissionregistrationv1.ValidatingWebhookConfiguration {
+	return ins.validatingWebhookConfigurations
+}
+
+func (ins *Strategy) MutatingWebhookConfigurations() []*admissionregistrationv1.MutatingWebhookConfiguration {
+	return ins.mutatingWebhookConfigurations
+}
+
+func (ins *Strategy) APIServices() []*apiregv1.APIService {
+	return ins.apiServices
+}
+
+func (ins *Strategy) CertificateSecrets() []*corev1.Secret {
+	return ins.certificateSecrets
+}
+
+func (ins *Strategy) SCCs() []*secv1.SecurityContextConstraints {
+	return ins.sccs
+}
+
+func (ins *Strategy) ServiceMonitors() []*promv1.ServiceMonitor {
+	return ins.serviceMonitors
+}
+
+func (ins *Strategy) PrometheusRules() []*promv1.PrometheusRule {
+	return ins.prometheusRules
+}
+
+func (ins *Strategy) ConfigMaps() []*corev1.ConfigMap {
+	return ins.configMaps
+}
+
+func (ins *Strategy) CRDs() []*extv1.CustomResourceDefinition {
+	return ins.crds
+}
+
+func (ins *Strategy) Routes() []*routev1.Route {
+	return ins.routes
+}
+
+// Namespaces returns the sorted, distinct set of namespaces the strategy's namespaced objects live in,
+// including the namespaces of service accounts bound by its (Cluster)RoleBindings
+func (ins *Strategy) Namespaces() []string {
+	namespaceSet := map[string]struct{}{}
+	add := func(namespace string) {
+		if namespace != "" {
+			namespaceSet[namespace] = struct{}{}
+		}
+	}
+
+	for _, sa := range ins.serviceAccounts {
+		add(sa.Namespace)
+	}
+	for _, r := range ins.roles {
+		add(r.Namespace)
+	}
+	for _, rb := range ins.roleBindings {
+		add(rb.Namespace)
+		for _, subject := range rb.Subjects {
+			add(subject.Namespace)
+		}
+	}
+	for _, crb := range ins.clusterRoleBindings {
+		for _, subject := range crb.Subjects {
+			add(subject.Namespace)
+		}
+	}
+	for _, service := range ins.services {
+		add(service.Namespace)
+	}
+	for _, deployment := range ins.deployments {
+		add(deployment.Namespace)
+	}
+	for _, daemonSet := range ins.daemonSets {
+		add(daemonSet.Namespace)
+	}
+	for _, secret := range ins.certificateSecrets {
+		add(secret.Namespace)
+	}
+	for _, serviceMonitor := range ins.serviceMonitors {
+		add(serviceMonitor.Namespace)
+	}
+	for _, prometheusRule := range ins.prometheusRules {
+		add(prometheusRule.Namespace)
+	}
+	for _, configMap := range ins.configMaps {
+		add(configMap.Namespace)
+	}
+	for _, route := range ins.routes {
+		add(route.Namespace)
+	}
+
+	namespaces := make([]string, 0, len(namespaceSet))
+	for namespace := range namespaceSet {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+
+	return namespaces
+}
+
+// CRDVersions returns the names of the served versions of every CRD of the strategy, keyed by CRD name
+func (ins *Strategy) CRDVersions() map[string][]string {
+	versions := make(map[string][]string, len(ins.crds))
+	for _, crd := range ins.crds {
+		served := []string{}
+		for _, version := range crd.Spec.Versions {
+			if version.Served {
+				served = append(served, version.Name)
+			}
+		}
+		versions[crd.Name] = served
+	}
+	return versions
+}
+
+// ImagesByComponent returns the images of all containers and init containers of the Deployments and DaemonSets of the
+// strategy, keyed by container name
+func (ins *Strategy) ImagesByComponent() map[string]string {
+	images := map[string]string{}
+	add := func(podSpec *corev1.PodSpec) {
+		for _, container := range append(append([]corev1.Container{}, podSpec.InitContainers...), podSpec.Containers...) {
+			images[container.Name] = container.Image
+		}
+	}
+
+	for _, deployment := range ins.deployments {
+		add(&deployment.Spec.Template.Spec)
+	}
+	for _, daemonSet := range ins.daemonSets {
+		add(&daemonSet.Spec.Template.Spec)
+	}
+	return images
+}
+
+// ObjectsForComponent returns the objects of the strategy labeled with the given app label value, together with the
+// ServiceAccounts the pods of the matching Deployments and DaemonSets run as
+func (ins *Strategy) ObjectsForComponent(appLabel string) []runtime.Object {
+	serviceAccounts := map[string]bool{}
+	addServiceAccount := func(namespace string, podSpec *corev1.PodSpec) {
+		if podSpec.ServiceAccountName != "" {
+			serviceAccounts[namespace+"/"+podSpec.ServiceAccountName] = true
+		}
+	}
+	for _, deployment := range ins.deployments {
+		if deployment.Labels[v1.AppLabel] == appLabel {
+			addServiceAccount(deployment.Namespace, &deployment.Spec.Template.Spec)
+		}
+	}
+	for _, daemonSet := range ins.daemonSets {
+		if daemonSet.Labels[v1.AppLabel] == appLabel {
+			addServiceAccount(daemonSet.Namespace, &daemonSet.Spec.Template.Spec)
+		}
+	}
+
+	var objects []runtime.Object
+	for _, o := range ins.objects() {
+		if o.obj.GetLabels()[v1.AppLabel] == appLabel ||
+			o.kind == "ServiceAccount" && serviceAccounts[o.obj.GetNamespace()+"/"+o.obj.GetName()] {
+			objects = append(objects, o.obj)
+		}
+	}
+	return objects
+}
+
+// WebhookEndpoint is an endpoint called by the API server for a webhook of the strategy. Either the service fields or
+// the URL are set, depending on the client config of the webhook.
+type WebhookEndpoint struct {
+	// Kind is ValidatingWebhookConfiguration or MutatingWebhookConfiguration
+	Kind string
+	// Configuration is the name of the webhook configuration
+	Configuration string
+	// Webhook is the name of the webhook within the configuration
+	Webhook          string
+	ServiceNamespace string
+	ServiceName      string
+	Path             string
+	Port             int32
+	URL              string
+}
+
+// defaultWebhookServicePort is the service port the API server calls if a webhook does not specify one
+const defaultWebhookServicePort = 443
+
+// Webhooks returns the endpoints of all validating and mutating webhooks of the strategy
+func (ins *Strategy) Webhooks() []WebhookEndpoint {
+	var endpoints []WebhookEndpoint
+	add := func(kind, configuration, webhook string, clientConfig admissionregistrationv1.WebhookClientConfig) {
+		endpoint := WebhookEndpoint{Kind: kind, Configuration: configuration, Webhook: webhook}
+		if clientConfig.URL != nil {
+			endpoint.URL = *clientConfig.URL
+		}
+		if service := clientConfig.Service; service != nil {
+			endpoint.ServiceNamespace = service.Namespace
+			endpoint.ServiceName = service.Name
+			endpoint.Port = defaultWebhookServicePort
+			if service.Path != nil {
+				endpoint.Path = *service.Path
+			}
+			if service.Port != nil {
+				endpoint.Port = *service.Port
+			}
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	for _, configuration := range ins.validatingWebhookConfigurations {
+		for _, webhook := range configuration.Webhooks {
+			add("ValidatingWebhookConfiguration", configuration.Name, webhook.Name, webhook.ClientConfig)
+		}
+	}
+	for _, configuration := range ins.mutatingWebhookConfigurations {
+		for _, webhook := range configuration.Webhooks {
+			add("MutatingWebhookConfiguration", configuration.Name, webhook.Name, webhook.ClientConfig)
+		}
+	}
+	return endpoints
+}
+
+// ResourceFootprint is the sum of the resource requests and limits of the workloads of a strategy
+type ResourceFootprint struct {
+	// Requests and Limits of all Deployments, multiplied by their replicas
+	Requests corev1.ResourceList
+	Limits   corev1.ResourceList
+	// PerNodeRequests and PerNodeLimits of all DaemonSets, which run one pod on every node they are scheduled to
+	PerNodeRequests corev1.ResourceList
+	PerNodeLimits   corev1.ResourceList
+}
+
+// ResourceFootprint returns the aggregated resource requests and limits of the Deployments and DaemonSets of the
+// strategy. Like the scheduler, it counts the larger of the sum of the containers and of every single init container
+// of a pod.
+func (ins *Strategy) ResourceFootprint() ResourceFootprint {
+	footprint := ResourceFootprint{
+		Requests:        corev1.ResourceList{},
+		Limits:          corev1.ResourceList{},
+		PerNodeRequests: corev1.ResourceList{},
+		PerNodeLimits:   corev1.ResourceList{},
+	}
+
+	for _, deployment := range ins.deployments {
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+		requests, limits := podResources(&deployment.Spec.Template.Spec)
+		for i := int32(0); i < replicas; i++ {
+			addResources(footprint.Requests, requests)
+			addResources(footprint.Limits, limits)
+		}
+	}
+	for _, daemonSet := range ins.daemonSets {
+		requests, limits := podResources(&daemonSet.Spec.Template.Spec)
+		addResources(footprint.PerNodeRequests, requests)
+		addResources(footprint.PerNodeLimits, limits)
+	}
+	return footprint
+}
+
+// podResources returns the effective requests and limits of a pod
+func podResources(podSpec *corev1.PodSpec) (requests corev1.ResourceList, limits corev1.ResourceList) {
+	requests, limits = corev1.ResourceList{}, corev1.ResourceList{}
+	for _, container := range podSpec.Containers {
+		addResources(requests, container.Resources.Requests)
+		addResources(limits, container.Resources.Limits)
+	}
+	for _, container := range podSpec.InitContainers {
+		maxResources(requests, container.Resources.Requests)
+		maxResources(limits, container.Resources.Limits)
+	}
+	return requests, limits
+}
+
+func addResources(total corev1.ResourceList, resources corev1.ResourceList) {
+	for name, quantity := range resources {
+		sum := total[name]
+		sum.Add(quantity)
+		total[name] = sum
+	}
+}
+
+func maxResources(total corev1.ResourceList, resources corev1.ResourceList) {
+	for name, quantity := range resources {
+		if current, exists := total[name]; !exists || quantity.Cmp(current) > 0 {
+			total[name] = quantity.DeepCopy()
+		}
+	}
+}
+
+// StrategyEqual reports whether both strategies contain the same objects, matched by kind and name. Server-set
+// metadata and the status of the objects are ignored.
+func StrategyEqual(a, b *Strategy) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	canonicalA, err := a.canonicalObjects()
+	if err != nil {
+		log.Log.Reason(err).Error("Failed to canonicalize install strategy")
+		return false
+	}
+	canonicalB, err := b.canonicalObjects()
+	if err != nil {
+		log.Log.Reason(err).Error("Failed to canonicalize install strategy")
+		return false
+	}
+
+	return reflect.DeepEqual(canonicalA, canonicalB)
+}
+
+// resources the objects of a strategy are applied as, by kind
+var strategyKindResources = map[string]schema.GroupVersionResource{
+	"Namespace":                      corev1.SchemeGroupVersion.WithResource("namespaces"),
+	"ServiceAccount":                 corev1.SchemeGroupVersion.WithResource("serviceaccounts"),
+	"ClusterRole":                    rbacv1.SchemeGroupVersion.WithResource("clusterroles"),
+	"ClusterRoleBinding":             rbacv1.SchemeGroupVersion.WithResource("clusterrolebindings"),
+	"Role":                           rbacv1.SchemeGroupVersion.WithResource("roles"),
+	"RoleBinding":                    rbacv1.SchemeGroupVersion.WithResource("rolebindings"),
+	"CustomResourceDefinition":       extv1.SchemeGroupVersion.WithResource("customresourcedefinitions"),
+	"Service":                        corev1.SchemeGroupVersion.WithResource("services"),
+	"Deployment":                     appsv1.SchemeGroupVersion.WithResource("deployments"),
+	"DaemonSet":                      appsv1.SchemeGroupVersion.WithResource("daemonsets"),
+	"ValidatingWebhookConfiguration": admissionregistrationv1.SchemeGroupVersion.WithResource("validatingwebhookconfigurations"),
+	"MutatingWebhookConfiguration":   admissionregistrationv1.SchemeGroupVersion.WithResource("mutatingwebhookconfigurations"),
+	"APIService":                     apiregv1.SchemeGroupVersion.WithResource("apiservices"),
+	"Secret":                         corev1.SchemeGroupVersion.WithResource("secrets"),
+	"SecurityContextConstraints":     secv1.GroupVersion.WithResource("securitycontextconstraints"),
+	"ServiceMonitor":                 promv1.SchemeGroupVersion.WithResource("servicemonitors"),
+	"PrometheusRule":                 promv1.SchemeGroupVersion.WithResource("prometheusrules"),
+	"ConfigMap":                      corev1.SchemeGroupVersion.WithResource("configmaps"),
+	"Route":                          routev1.GroupVersion.WithResource("routes"),
+}
+
+// verbs the operator uses to apply the objects of a strategy
+var applyVerbs = []string{"get", "list", "watch", "create", "update", "patch", "delete"}
+
+// RequiredApplyPermissions returns the RBAC rules needed to apply the strategy, one rule per kind of object in the
+// strategy
+func RequiredApplyPermissions(strategy *Strategy) []rbacv1.PolicyRule {
+	var rules []rbacv1.PolicyRule
+	seen := map[string]bool{}
+	for _, o := range strategy.objects() {
+		if seen[o.kind] {
+			continue
+		}
+		seen[o.kind] = true
+
+		resource := strategyKindResources[o.kind]
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{resource.Group},
+			Resources: []string{resource.Resource},
+			Verbs:     append([]string{}, applyVerbs...),
+		})
+	}
+	return rules
+}
+
+// strategyObject is an object of the strategy together with its kind
+type strategyObject struct {
+	kind string
+	obj  interface {
+		runtime.Object
+		metav1.Object
+	}
+}
+
+// objects returns all objects of the strategy with their kinds
+func (ins *Strategy) objects() []strategyObject {
+	var objects []strategyObject
+	// namespaces have to come first, everything else may live in them
+	for _, obj := range ins.namespaces {
+		objects = append(objects, strategyObject{"Namespace", obj})
+	}
+	for _, obj := range ins.serviceAccounts {
+		objects = append(objects, strategyObject{"ServiceAccount", obj})
+	}
+	for _, obj := range ins.clusterRoles {
+		objects = append(objects, strategyObject{"ClusterRole", obj})
+	}
+	for _, obj := range ins.clusterRoleBindings {
+		objects = append(objects, strategyObject{"ClusterRoleBinding", obj})
+	}
+	for _, obj := range ins.roles {
+		objects = append(objects, strategyObject{"Role", obj})
+	}
+	for _, obj := range ins.roleBindings {
+		objects = append(objects, strategyObject{"RoleBinding", obj})
+	}
+	for _, obj := range ins.crds {
+		objects = append(objects, strategyObject{"CustomResourceDefinition", obj})
+	}
+	for _, obj := range ins.services {
+		objects = append(objects, strategyObject{"Service", obj})
+	}
+	for _, obj := range ins.deployments {
+		objects = append(objects, strategyObject{"Deployment", obj})
+	}
+	for _, obj := range ins.daemonSets {
+		objects = append(objects, strategyObject{"DaemonSet", obj})
+	}
+	for _, obj := range ins.validatingWebhookConfigurations {
+		objects = append(objects, strategyObject{"ValidatingWebhookConfiguration", obj})
+	}
+	for _, obj := range ins.mutatingWebhookConfigurations {
+		objects = append(objects, strategyObject{"MutatingWebhookConfiguration", obj})
+	}
+	for _, obj := range ins.apiServices {
+		objects = append(objects, strategyObject{"APIService", obj})
+	}
+	for _, obj := range ins.certificateSecrets {
+		objects = append(objects, strategyObject{"Secret", obj})
+	}
+	for _, obj := range ins.sccs {
+		objects = append(objects, strategyObject{"SecurityContextConstraints", obj})
+	}
+	for _, obj := range ins.serviceMonitors {
+		objects = append(objects, strategyObject{"ServiceMonitor", obj})
+	}
+	for _, obj := range ins.prometheusRules {
+		objects = append(objects, strategyObject{"PrometheusRule", obj})
+	}
+	for _, obj := range ins.configMaps {
+		objects = append(objects, strategyObject{"ConfigMap", obj})
+	}
+	for _, obj := range ins.routes {
+		objects = append(objects, strategyObject{"Route", obj})
+	}
+	return objects
+}
+
+// OrderedObjects returns all objects of the strategy in the order they are applied in. Objects are grouped by kind,
+// an object with a DependsOnAnnotation is moved behind the objects it depends on. Dependencies which are not part of
+// the strategy are ignored, circular dependencies are an error.
+func (ins *Strategy) OrderedObjects() ([]runtime.Object, error) {
+	ordered, err := ins.orderedObjects()
+	if err != nil {
+		return nil, err
+	}
+	objects := make([]runtime.Object, 0, len(ordered))
+	for _, o := range ordered {
+		objects = append(objects, o.obj)
+	}
+	return objects, nil
+}
+
+func (ins *Strategy) orderedObjects() ([]strategyObject, error) {
+	objects := ins.objects()
+	byIdentity := make(map[objectIdentity]int, len(objects))
+	for i, o := range objects {
+		if _, exists := byIdentity[o.identity()]; !exists {
+			byIdentity[o.identity()] = i
+		}
+	}
+
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := make([]int, len(objects))
+	ordered := make([]strategyObject, 0, len(objects))
+
+	var visit func(i int) error
+	visit = func(i int) error {
+		o := objects[i]
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("%s %s is part of a dependency cycle", o.kind, o.obj.GetName())
+		}
+		state[i] = visiting
+
+		if hint := o.obj.GetAnnotations()[DependsOnAnnotation]; hint != "" {
+			for _, dependency := range strings.Split(hint, ",") {
+				kind, name, ok := strings.Cut(strings.TrimSpace(dependency), "/")
+				if !ok {
+					return fmt.Errorf("%s %s has an invalid dependency %q, expected Kind/name", o.kind, o.obj.GetName(), dependency)
+				}
+				id := objectIdentity{Kind: kind, Name: name}
+				if !clusterScopedKinds[kind] {
+					id.Namespace = o.obj.GetNamespace()
+				}
+				if d, exists := byIdentity[id]; exists {
+					if err := visit(d); err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		state[i] = visited
+		ordered = append(ordered, o)
+		return nil
+	}
+
+	for i := range objects {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// addObject adds an object returned by objects to the strategy
+func (ins *Strategy) addObject(obj runtime.Object) {
+	switch obj := obj.(type) {
+	case *corev1.Namespace:
+		ins.namespaces = append(ins.namespaces, obj)
+	case *corev1.ServiceAccount:
+		ins.serviceAccounts = append(ins.serviceAccounts, obj)
+	case *rbacv1.ClusterRole:
+		ins.clusterRoles = append(ins.clusterRoles, obj)
+	case *rbacv1.ClusterRoleBinding:
+		ins.clusterRoleBindings = append(ins.clusterRoleBindings, obj)
+	case *rbacv1.Role:
+		ins.roles = append(ins.roles, obj)
+	case *rbacv1.RoleBinding:
+		ins.roleBindings = append(ins.roleBindings, obj)
+	case *extv1.CustomResourceDefinition:
+		ins.crds = append(ins.crds, obj)
+	case *corev1.Service:
+		ins.services = append(ins.services, obj)
+	case *appsv1.Deployment:
+		ins.deployments = append(ins.deployments, obj)
+	case *appsv1.DaemonSet:
+		ins.daemonSets = append(ins.daemonSets, obj)
+	case *admissionregistrationv1.ValidatingWebhookConfiguration:
+		ins.validatingWebhookConfigurations = append(ins.validatingWebhookConfigurations, obj)
+	case *admissionregistrationv1.MutatingWebhookConfiguration:
+		ins.mutatingWebhookConfigurations = append(ins.mutatingWebhookConfigurations, obj)
+	case *apiregv1.APIService:
+		ins.apiServices = append(ins.apiServices, obj)
+	case *corev1.Secret:
+		ins.certificateSecrets = append(ins.certificateSecrets, obj)
+	case *secv1.SecurityContextConstraints:
+		ins.sccs = append(ins.sccs, obj)
+	case *promv1.ServiceMonitor:
+		ins.serviceMonitors = append(ins.serviceMonitors, obj)
+	case *promv1.PrometheusRule:
+		ins.prometheusRules = append(ins.prometheusRules, obj)
+	case *corev1.ConfigMap:
+		ins.configMaps = append(ins.configMaps, obj)
+	case *routev1.Route:
+		ins.routes = append(ins.routes, obj)
+	}
+}
+
+// objectIdentity identifies an object of a strategy
+type objectIdentity struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+func (o strategyObject) identity() objectIdentity {
+	return objectIdentity{Kind: o.kind, Namespace: o.obj.GetNamespace(), Name: o.obj.GetName()}
+}
+
+// distinctObjects returns the objects with only the first one of each kind, namespace and name, some objects like the
+// CA config maps are added to the strategy twice
+func distinctObjects(objects []strategyObject) []strategyObject {
+	seen := make(map[objectIdentity]bool, len(objects))
+	distinct := make([]strategyObject, 0, len(objects))
+	for _, o := range objects {
+		if seen[o.identity()] {
+			continue
+		}
+		seen[o.identity()] = true
+		distinct = append(distinct, o)
+	}
+	return distinct
+}
+
+// canonicalObjects returns all objects of the strategy keyed by kind, namespace and name, with server-set metadata
+// and the status removed
+func (ins *Strategy) canonicalObjects() (map[objectIdentity]map[string]interface{}, error) {
+	objects := map[objectIdentity]map[string]interface{}{}
+	for _, o := range ins.objects() {
+		canonical, err := canonicalObject(o.obj)
+		if err != nil {
+			return nil, err
+		}
+		objects[o.identity()] = canonical
+	}
+	return objects, nil
+}
+
+// canonicalObject returns the content of the object with the type, server-set metadata and the status removed
+func canonicalObject(obj runtime.Object) (map[string]interface{}, error) {
+	canonical, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	delete(canonical, "apiVersion")
+	delete(canonical, "kind")
+	delete(canonical, "status")
+	if meta, ok := canonical["metadata"].(map[string]interface{}); ok {
+		for _, field := range []string{"uid", "resourceVersion", "generation", "creationTimestamp", "selfLink", "managedFields"} {
+			delete(meta, field)
+		}
+	}
+	return canonical, nil
+}
+
+// operator-injected metadata, which is added by the Reconciler and not part of the generated objects
+var (
+	operatorInjectedLabels = []string{
+		v1.AppVersionLabel,
+		v1.AppPartOfLabel,
+		v1.AppComponentLabel,
+		v1.ManagedByLabel,
+	}
+	operatorInjectedAnnotations = []string{
+		v1.InstallStrategyVersionAnnotation,
+		v1.InstallStrategyRegistryAnnotation,
+		v1.InstallStrategyIdentifierAnnotation,
+		v1.KubeVirtGenerationAnnotation,
+		v1.KubeVirtCustomizeComponentAnnotationHash,
+		"deployment.kubernetes.io/revision",
+	}
+)
+
+// serverDefaults are the values the API server defaults fields of the generated objects to. Fields are only ignored
+// while they hold the default value.
+var serverDefaults = map[string]interface{}{
+	"dnsPolicy":                     string(corev1.DNSClusterFirst),
+	"restartPolicy":                 string(corev1.RestartPolicyAlways),
+	"schedulerName":                 corev1.DefaultSchedulerName,
+	"terminationGracePeriodSeconds": int64(corev1.DefaultTerminationGracePeriodSeconds),
+	"terminationMessagePath":        corev1.TerminationMessagePathDefault,
+	"terminationMessagePolicy":      string(corev1.TerminationMessageReadFile),
+	"revisionHistoryLimit":          int64(10),
+	"progressDeadlineSeconds":       int64(600),
+	"protocol":                      string(corev1.ProtocolTCP),
+	"sessionAffinity":               string(corev1.ServiceAffinityNone),
+	"internalTrafficPolicy":         string(corev1.ServiceInternalTrafficPolicyCluster),
+	"securityContext":               map[string]interface{}{},
+}
+
+// serviceAllocatedFields are allocated by the API server when a Service is created
+var serviceAllocatedFields = []string{"clusterIP", "clusterIPs", "ipFamilies", "ipFamilyPolicy"}
+
+// ObjectFingerprint returns a hash over the canonicalized content of the object. It ignores the fields set by the
+// server, the metadata injected by the operator and fields holding the API server defaults, so a generated object
+// and the same object read back from the cluster share a fingerprint.
+func ObjectFingerprint(obj runtime.Object) (string, error) {
+	canonical, err := canonicalObject(obj)
+	if err != nil {
+		return "", fmt.Errorf("failed to canonicalize object: %v", err)
+	}
+	removeOperatorMetadata(canonical)
+	if spec, ok := canonical["spec"].(map[string]interface{}); ok {
+		if template, ok := spec["template"].(map[string]interface{}); ok {
+			removeOperatorMetadata(template)
+		}
+		if _, isService := obj.(*corev1.Service); isService {
+			for _, field := range serviceAllocatedFields {
+				delete(spec, field)
+			}
+		}
+	}
+	removeServerDefaults(canonical)
+
+	// maps are marshalled with sorted keys, which keeps the content stable
+	content, err := json.Marshal(canonical)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal object: %v", err)
+	}
+
+	// #nosec CWE: 326 - Use of weak cryptographic primitive (http://cwe.mitre.org/data/definitions/326.html)
+	// reason: sha1 is not used for encryption but for creating a hash value
+	hasher := sha1.New()
+	hasher.Write(content)
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// removeOperatorMetadata removes the operator-injected labels and annotations from the metadata of the object, empty
+// label and annotation maps are removed as well
+func removeOperatorMetadata(object map[string]interface{}) {
+	meta, ok := object["metadata"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for field, keys := range map[string][]string{"labels": operatorInjectedLabels, "annotations": operatorInjectedAnnotations} {
+		values, ok := meta[field].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, key := range keys {
+			delete(values, key)
+		}
+		if len(values) == 0 {
+			delete(meta, field)
+		}
+	}
+}
+
+// removeServerDefaults recursively removes all fields which hold their API server default
+func removeServerDefaults(value interface{}) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, field := range value {
+			if defaultValue, ok := serverDefaults[key]; ok && reflect.DeepEqual(field, defaultValue) {
+				delete(value, key)
+				continue
+			}
+			removeServerDefaults(field)
+		}
+	case []interface{}:
+		for _, item := range value {
+			removeServerDefaults(item)
+		}
+	}
+}
+
+// addObjectFinalizer adds the finalizer to all objects except CRDs and namespaces, which have to stay until all other
+// objects are gone
+func (ins *Strategy) addObjectFinalizer(finalizer string) {
+	for _, o := range ins.objects() {
+		if o.kind == "CustomResourceDefinition" || o.kind == "Namespace" {
+			continue
+		}
+		controller.AddFinalizer(o.obj, finalizer)
+	}
+}
+
+// addRBACAnnotations adds the annotations to all ClusterRoles, ClusterRoleBindings, Roles and RoleBindings
+func (ins *Strategy) addRBACAnnotations(annotations map[string]string) {
+	for _, o := range ins.objects() {
+		switch o.kind {
+		case "ClusterRole", "ClusterRoleBinding", "Role", "RoleBinding":
+		default:
+			continue
+		}
+		objAnnotations := o.obj.GetAnnotations()
+		if objAnnotations == nil {
+			objAnnotations = map[string]string{}
+		}
+		for key, value := range annotations {
+			objAnnotations[key] = value
+		}
+		o.obj.SetAnnotations(objAnnotations)
+	}
+}
+
+// setSessionAffinity enables ClientIP session affinity with the given timeouts, keyed by service name, on the services
+// of the install namespace
+func (ins *Strategy) setSessionAffinity(namespace string, timeouts map[string]int32) error {
+	services := map[string]*corev1.Service{}
+	for _, service := range ins.services {
+		if service.Namespace == namespace {
+			services[service.Name] = service
+		}
+	}
+
+	names := make([]string, 0, len(timeouts))
+	for name := range timeouts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		service, exists := services[name]
+		if !exists {
+			return fmt.Errorf("session affinity for unknown service %s", name)
+		}
+		timeout := timeouts[name]
+		if timeout < 1 || timeout > components.MaxClientIPSessionAffinitySeconds {
+			return fmt.Errorf("invalid session affinity timeout %d for service %s, it has to be between 1 and %d seconds", timeout, name, components.MaxClientIPSessionAffinitySeconds)
+		}
+		components.SetClientIPSessionAffinity(service, timeout)
+	}
+	return nil
+}
+
+// addReadinessGates adds readiness gates with the given condition types, keyed by Deployment name, to the pods of the
+// Deployments of the install namespace
+func (ins *Strategy) addReadinessGates(namespace string, gates map[string][]string) error {
+	deployments := map[string]*appsv1.Deployment{}
+	for _, deployment := range ins.deployments {
+		if deployment.Namespace == namespace {
+			deployments[deployment.Name] = deployment
+		}
+	}
+
+	names := make([]string, 0, len(gates))
+	for name := range gates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		deployment, exists := deployments[name]
+		if !exists {
+			return fmt.Errorf("readiness gates for unknown deployment %s", name)
+		}
+		podSpec := &deployment.Spec.Template.Spec
+		for _, conditionType := range gates[name] {
+			if conditionType == "" {
+				return fmt.Errorf("empty readiness gate condition type for deployment %s", name)
+			}
+			exists := false
+			for _, gate := range podSpec.ReadinessGates {
+				exists = exists || gate.ConditionType == corev1.PodConditionType(conditionType)
+			}
+			if !exists {
+				podSpec.ReadinessGates = append(podSpec.ReadinessGates, corev1.PodReadinessGate{ConditionType: corev1.PodConditionType(conditionType)})
+			}
+		}
+	}
+	return nil
+}
+
+// addStartupProbes sets the startup probes, keyed by component name, on the containers of the components of the
+// install namespace. A probe without a handler reuses the one of the liveness or readiness probe of the container.
+func (ins *Strategy) addStartupProbes(namespace string, probes map[string]corev1.Probe) error {
+	podSpecs := map[string]*corev1.PodSpec{}
+	for _, deployment := range ins.deployments {
+		if deployment.Namespace == namespace {
+			podSpecs[deployment.Name] = &deployment.Spec.Template.Spec
+		}
+	}
+	for _, daemonSet := range ins.daemonSets {
+		if daemonSet.Namespace == namespace {
+			podSpecs[daemonSet.Name] = &daemonSet.Spec.Template.Spec
+		}
+	}
+
+	componentNames := make([]string, 0, len(probes))
+	for component := range probes {
+		componentNames = append(componentNames, component)
+	}
+	sort.Strings(componentNames)
+
+	for _, component := range componentNames {
+		podSpec, exists := podSpecs[component]
+		if !exists {
+			return fmt.Errorf("startup probe for unknown component %s", component)
+		}
+		found := false
+		for i := range podSpec.Containers {
+			container := &podSpec.Containers[i]
+			if container.Name != component {
+				continue
+			}
+			probe := probes[component]
+			if reflect.DeepEqual(probe.ProbeHandler, corev1.ProbeHandler{}) {
+				switch {
+				case container.LivenessProbe != nil:
+					probe.ProbeHandler = *container.LivenessProbe.ProbeHandler.DeepCopy()
+				case container.ReadinessProbe != nil:
+					probe.ProbeHandler = *container.ReadinessProbe.ProbeHandler.DeepCopy()
+				default:
+					return fmt.Errorf("startup probe for component %s has no handler and the container has no probe to take it from", component)
+				}
+			}
+			container.StartupProbe = &probe
+			found = true
+		}
+		if !found {
+			return fmt.Errorf("component %s has no container named %s", component, component)
+		}
+	}
+	return nil
+}
+
+// addVersionLabel labels all objects of the strategy with the release version, the same label the Reconciler sets
+// from the product version
+func (ins *Strategy) addVersionLabel(version string) {
+	for _, o := range ins.objects() {
+		labels := o.obj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[v1.AppVersionLabel] = version
+		o.obj.SetLabels(labels)
+	}
+}
+
+// addConfigMapChecksums sets ConfigMapChecksumAnnotation on the pod template of every Deployment which references a
+// ConfigMap of the strategy through a volume, a projected volume or an environment variable
+func (ins *Strategy) addConfigMapChecksums() error {
+	configMaps := map[string]*corev1.ConfigMap{}
+	for _, configMap := range ins.configMaps {
+		configMaps[configMap.Namespace+"/"+configMap.Name] = configMap
+	}
+
+	for _, deployment := range ins.deployments {
+		var referenced []string
+		for _, name := range referencedConfigMaps(&deployment.Spec.Template.Spec) {
+			if _, exists := configMaps[deployment.Namespace+"/"+name]; exists {
+				referenced = append(referenced, name)
+			}
+		}
+		if len(referenced) == 0 {
+			continue
+		}
+		sort.Strings(referenced)
+
+		// #nosec CWE: 326 - Use of weak cryptographic primitive (http://cwe.mitre.org/data/definitions/326.html)
+		// reason: sha1 is not used for encryption but for creating a hash value
+		hasher := sha1.New()
+		for _, name := range referenced {
+			configMap := configMaps[deployment.Namespace+"/"+name]
+			content, err := json.Marshal(struct {
+				Name       string            `json:"name"`
+				Data       map[string]string `json:"data,omitempty"`
+				BinaryData map[string][]byte `json:"binaryData,omitempty"`
+			}{name, configMap.Data, configMap.BinaryData})
+			if err != nil {
+				return err
+			}
+			hasher.Write(content)
+		}
+
+		if deployment.Spec.Template.Annotations == nil {
+			deployment.Spec.Template.Annotations = map[string]string{}
 		}
-		deployments = append(deployments, deployment)
+		deployment.Spec.Template.Annotations[ConfigMapChecksumAnnotation] = hex.EncodeToString(hasher.Sum(nil))
 	}
-
-	return deployments
+	return nil
 }
 
-func (ins *Strategy) ControllerDeployments() []*appsv1.Deployment {
-	var deployments []*appsv1.Deployment
+// referencedConfigMaps returns the names of the ConfigMaps used by the volumes and containers of a pod, without
+// duplicates
+func referencedConfigMaps(podSpec *corev1.PodSpec) []string {
+	seen := map[string]bool{}
+	var names []string
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
 
-	for _, deployment := range ins.deployments {
-		if !strings.Contains(deployment.Name, "virt-controller") {
-			continue
+	for _, volume := range podSpec.Volumes {
+		if volume.ConfigMap != nil {
+			add(volume.ConfigMap.Name)
 		}
-		deployments = append(deployments, deployment)
+		if volume.Projected != nil {
+			for _, source := range volume.Projected.Sources {
+				if source.ConfigMap != nil {
+					add(source.ConfigMap.Name)
+				}
+			}
+		}
+	}
+	for _, container := range append(append([]corev1.Container{}, podSpec.InitContainers...), podSpec.Containers...) {
+		for _, envFrom := range container.EnvFrom {
+			if envFrom.ConfigMapRef != nil {
+				add(envFrom.ConfigMapRef.Name)
+			}
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom != nil && env.ValueFrom.ConfigMapKeyRef != nil {
+				add(env.ValueFrom.ConfigMapKeyRef.Name)
+			}
+		}
+	}
+	return names
+}
 
+// applyObjectPatches applies JSON patches to objects of the strategy. The patches are keyed by "Kind/name" and every
+// patched object has to exist.
+func (ins *Strategy) applyObjectPatches(patches map[string]string) error {
+	targets := map[string]strategyObject{}
+	for _, o := range ins.objects() {
+		targets[o.kind+"/"+o.obj.GetName()] = o
 	}
 
-	return deployments
+	// apply in a stable order to get reproducible errors
+	keys := make([]string, 0, len(patches))
+	for key := range patches {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		target, exists := targets[key]
+		if !exists {
+			return fmt.Errorf("patch target %s does not exist in the install strategy", key)
+		}
+		patch, err := jsonpatch.DecodePatch([]byte(patches[key]))
+		if err != nil {
+			return fmt.Errorf("invalid patch for %s: %v", key, err)
+		}
+		original, err := json.Marshal(target.obj)
+		if err != nil {
+			return err
+		}
+		modified, err := patch.Apply(original)
+		if err != nil {
+			return fmt.Errorf("failed to patch %s: %v", key, err)
+		}
+		// reset the object, unmarshal does not clear fields which were removed by the patch
+		value := reflect.ValueOf(target.obj)
+		value.Elem().Set(reflect.New(value.Type().Elem()).Elem())
+		if err := json.Unmarshal(modified, target.obj); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func (ins *Strategy) ExportProxyDeployments() []*appsv1.Deployment {
-	var deployments []*appsv1.Deployment
+// overrideServiceAccountNames renames the service accounts of components in the install namespace. The overrides are
+// keyed by component name and are applied to the ServiceAccounts, the pod templates, the binding subjects and the SCC
+// users referring to them.
+func (ins *Strategy) overrideServiceAccountNames(namespace string, overrides map[string]string) error {
+	componentNames := make([]string, 0, len(overrides))
+	for component := range overrides {
+		componentNames = append(componentNames, component)
+	}
+	sort.Strings(componentNames)
 
-	for _, deployment := range ins.deployments {
-		if !strings.Contains(deployment.Name, "virt-exportproxy") {
-			continue
+	renames := map[string]string{}
+	for _, component := range componentNames {
+		defaultName, exists := components.ComponentServiceAccountNames[component]
+		if !exists {
+			return fmt.Errorf("service account override for unknown component %s", component)
 		}
-		deployments = append(deployments, deployment)
-
+		name := overrides[component]
+		if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+			return fmt.Errorf("invalid service account name %q for %s: %s", name, component, strings.Join(errs, ", "))
+		}
+		renames[defaultName] = name
+	}
+	if len(renames) == 0 {
+		return nil
 	}
 
-	return deployments
-}
+	rename := func(name *string) {
+		if newName, exists := renames[*name]; exists {
+			*name = newName
+		}
+	}
+	renameSubjects := func(subjects []rbacv1.Subject) {
+		for i := range subjects {
+			if subjects[i].Kind == rbacv1.ServiceAccountKind && subjects[i].Namespace == namespace {
+				rename(&subjects[i].Name)
+			}
+		}
+	}
 
-func (ins *Strategy) DaemonSets() []*appsv1.DaemonSet {
-	return ins.daemonSets
+	for _, sa := range ins.serviceAccounts {
+		if sa.Namespace == namespace {
+			rename(&sa.Name)
+		}
+	}
+	for _, deployment := range ins.deployments {
+		if deployment.Namespace == namespace {
+			rename(&deployment.Spec.Template.Spec.ServiceAccountName)
+		}
+	}
+	for _, daemonSet := range ins.daemonSets {
+		if daemonSet.Namespace == namespace {
+			rename(&daemonSet.Spec.Template.Spec.ServiceAccountName)
+		}
+	}
+	for _, binding := range ins.clusterRoleBindings {
+		renameSubjects(binding.Subjects)
+	}
+	for _, binding := range ins.roleBindings {
+		renameSubjects(binding.Subjects)
+	}
+	userPrefix := fmt.Sprintf("system:serviceaccount:%s:", namespace)
+	for _, scc := range ins.sccs {
+		for i, user := range scc.Users {
+			if strings.HasPrefix(user, userPrefix) {
+				name := strings.TrimPrefix(user, userPrefix)
+				rename(&name)
+				scc.Users[i] = userPrefix + name
+			}
+		}
+	}
+	return nil
 }
 
-func (ins *Strategy) ValidatingWebhookConfigurations() []*admissionregistrationv1.ValidatingWebhookConfiguration {
-	return ins.validatingWebhookConfigurations
-}
+// overrideContainers replaces the command and args of the component containers in the install namespace. The
+// overrides are keyed by component name, which is also the name of the component's workload and container.
+func (ins *Strategy) overrideContainers(namespace string, overrides map[string]util.ContainerOverride) error {
+	podSpecs := map[string]*corev1.PodSpec{}
+	for _, deployment := range ins.deployments {
+		if deployment.Namespace == namespace {
+			podSpecs[deployment.Name] = &deployment.Spec.Template.Spec
+		}
+	}
+	for _, daemonSet := range ins.daemonSets {
+		if daemonSet.Namespace == namespace {
+			podSpecs[daemonSet.Name] = &daemonSet.Spec.Template.Spec
+		}
+	}
 
-func (ins *Strategy) MutatingWebhookConfigurations() []*admissionregistrationv1.MutatingWebhookConfiguration {
-	return ins.mutatingWebhookConfigurations
-}
+	componentNames := make([]string, 0, len(overrides))
+	for component := range overrides {
+		componentNames = append(componentNames, component)
+	}
+	sort.Strings(componentNames)
 
-func (ins *Strategy) APIServices() []*apiregv1.APIService {
-	return ins.apiServices
+	for _, component := range componentNames {
+		podSpec, exists := podSpecs[component]
+		if !exists {
+			return fmt.Errorf("container override for unknown component %s", component)
+		}
+		override := overrides[component]
+		found := false
+		for i := range podSpec.Containers {
+			container := &podSpec.Containers[i]
+			if container.Name != component {
+				continue
+			}
+			if override.Command != nil {
+				container.Command = append([]string{}, override.Command...)
+			}
+			if override.Args != nil {
+				container.Args = append([]string{}, override.Args...)
+			}
+			found = true
+		}
+		if !found {
+			return fmt.Errorf("component %s has no container named %s", component, component)
+		}
+	}
+	return nil
 }
 
-func (ins *Strategy) CertificateSecrets() []*corev1.Secret {
-	return ins.certificateSecrets
-}
+const redactedValue = "***"
 
-func (ins *Strategy) SCCs() []*secv1.SecurityContextConstraints {
-	return ins.sccs
+// DefaultRedactKeyPatterns match the Secret and ConfigMap keys which RedactSecrets hides by default
+var DefaultRedactKeyPatterns = []string{
+	`(?i)password`,
+	`(?i)passwd`,
+	`(?i)token`,
+	`(?i)secret`,
+	`(?i)credential`,
+	`(?i)(^|[._-])key$`,
 }
 
-func (ins *Strategy) ServiceMonitors() []*promv1.ServiceMonitor {
-	return ins.serviceMonitors
-}
+// RedactSecrets returns a copy of the strategy which is safe to log. Values of Secret and ConfigMap keys matching any
+// of the patterns are replaced, DefaultRedactKeyPatterns are used if no patterns are given. All other objects are
+// shared with the original strategy.
+func RedactSecrets(strategy *Strategy, keyPatterns ...string) (*Strategy, error) {
+	if len(keyPatterns) == 0 {
+		keyPatterns = DefaultRedactKeyPatterns
+	}
+	var patterns []*regexp.Regexp
+	for _, keyPattern := range keyPatterns {
+		pattern, err := regexp.Compile(keyPattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redact pattern %q: %v", keyPattern, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	sensitive := func(key string) bool {
+		for _, pattern := range patterns {
+			if pattern.MatchString(key) {
+				return true
+			}
+		}
+		return false
+	}
 
-func (ins *Strategy) PrometheusRules() []*promv1.PrometheusRule {
-	return ins.prometheusRules
-}
+	redacted := *strategy
 
-func (ins *Strategy) ConfigMaps() []*corev1.ConfigMap {
-	return ins.configMaps
-}
+	redacted.certificateSecrets = make([]*corev1.Secret, 0, len(strategy.certificateSecrets))
+	for _, secret := range strategy.certificateSecrets {
+		secret = secret.DeepCopy()
+		for key := range secret.Data {
+			if sensitive(key) {
+				secret.Data[key] = []byte(redactedValue)
+			}
+		}
+		for key := range secret.StringData {
+			if sensitive(key) {
+				secret.StringData[key] = redactedValue
+			}
+		}
+		redacted.certificateSecrets = append(redacted.certificateSecrets, secret)
+	}
 
-func (ins *Strategy) CRDs() []*extv1.CustomResourceDefinition {
-	return ins.crds
-}
+	redacted.configMaps = make([]*corev1.ConfigMap, 0, len(strategy.configMaps))
+	for _, configMap := range strategy.configMaps {
+		configMap = configMap.DeepCopy()
+		for key := range configMap.Data {
+			if sensitive(key) {
+				configMap.Data[key] = redactedValue
+			}
+		}
+		for key := range configMap.BinaryData {
+			if sensitive(key) {
+				configMap.BinaryData[key] = []byte(redactedValue)
+			}
+		}
+		redacted.configMaps = append(redacted.configMaps, configMap)
+	}
 
-func (ins *Strategy) Routes() []*routev1.Route {
-	return ins.routes
+	return &redacted, nil
 }
 
 func encodeManifests(manifests []byte) (string, error) {
@@ -224,6 +1412,20 @@ func encodeManifests(manifests []byte) (string, error) {
 	return base64Strategy, nil
 }
 
+// encodeInstallStrategy dumps and encodes the strategy for the install strategy ConfigMap. It fails if the encoded
+// strategy would exceed the ConfigMap size limit, instead of leaving the API server to reject the ConfigMap.
+func encodeInstallStrategy(strategy *Strategy) (string, error) {
+	dumped := dumpInstallStrategyToBytes(strategy)
+	manifests, err := encodeManifests(dumped)
+	if err != nil {
+		return "", err
+	}
+	if len(manifests) > maxInstallStrategyConfigMapSize {
+		return "", fmt.Errorf("the install strategy is %d bytes (%d bytes uncompressed) and exceeds the ConfigMap size limit of %d bytes even with %s encoding, reduce the size of the generated objects, e.g. of object patches and extra volumes", len(manifests), len(dumped), maxInstallStrategyConfigMapSize, ManifestsEncodingGzipBase64)
+	}
+	return manifests, nil
+}
+
 func decodeManifests(strategy []byte) (string, error) {
 	var decodedStrategy strings.Builder
 
@@ -248,7 +1450,7 @@ func NewInstallStrategyConfigMap(config *operatorutil.KubeVirtDeploymentConfig,
 		return nil, err
 	}
 
-	manifests, err := encodeManifests(dumpInstallStrategyToBytes(strategy))
+	manifests, err := encodeInstallStrategy(strategy)
 	if err != nil {
 		return nil, err
 	}
@@ -272,6 +1474,10 @@ func NewInstallStrategyConfigMap(config *operatorutil.KubeVirtDeploymentConfig,
 			"manifests": manifests,
 		},
 	}
+	if config.ImmutableInstallStrategyEnabled() {
+		immutable := true
+		configMap.Immutable = &immutable
+	}
 	return configMap, nil
 }
 
@@ -309,20 +1515,43 @@ func DumpInstallStrategyToConfigMap(clientset kubecli.KubevirtClient, operatorNa
 		return err
 	}
 
-	_, err = clientset.CoreV1().ConfigMaps(config.GetNamespace()).Create(context.Background(), configMap, metav1.CreateOptions{})
+	return storeInstallStrategyConfigMap(clientset.CoreV1(), configMap)
+}
+
+// storeInstallStrategyConfigMap creates the install strategy config map or overwrites an existing one. Immutable
+// config maps can't be updated, they are deleted and created again instead.
+func storeInstallStrategyConfigMap(core k8coresv1.CoreV1Interface, configMap *corev1.ConfigMap) error {
+	configMaps := core.ConfigMaps(configMap.Namespace)
+	_, err := configMaps.Create(context.Background(), configMap, metav1.CreateOptions{})
+	if err == nil || !errors.IsAlreadyExists(err) {
+		return err
+	}
+	if configMap.Name == "" {
+		// the generated name is taken, a new one is generated on the next attempt
+		_, err = configMaps.Create(context.Background(), configMap, metav1.CreateOptions{})
+		return err
+	}
+
+	existing, err := configMaps.Get(context.Background(), configMap.Name, metav1.GetOptions{})
 	if err != nil {
-		if errors.IsAlreadyExists(err) {
-			// force update if already exists
-			_, err = clientset.CoreV1().ConfigMaps(config.GetNamespace()).Update(context.Background(), configMap, metav1.UpdateOptions{})
-			if err != nil {
-				return err
-			}
-		} else {
-			return err
-		}
+		return err
+	}
+	if !isImmutableConfigMap(existing) && !isImmutableConfigMap(configMap) {
+		// force update if already exists
+		_, err = configMaps.Update(context.Background(), configMap, metav1.UpdateOptions{})
+		return err
 	}
 
-	return nil
+	err = configMaps.Delete(context.Background(), configMap.Name, metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	_, err = configMaps.Create(context.Background(), configMap, metav1.CreateOptions{})
+	return err
+}
+
+func isImmutableConfigMap(configMap *corev1.ConfigMap) bool {
+	return configMap.Immutable != nil && *configMap.Immutable
 }
 
 func dumpInstallStrategyToBytes(strategy *Strategy) []byte {
@@ -330,6 +1559,9 @@ func dumpInstallStrategyToBytes(strategy *Strategy) []byte {
 	var b bytes.Buffer
 	writer := bufio.NewWriter(&b)
 
+	for _, entry := range strategy.namespaces {
+		marshalutil.MarshallObject(entry, writer)
+	}
 	for _, entry := range strategy.serviceAccounts {
 		marshalutil.MarshallObject(entry, writer)
 	}
@@ -395,6 +1627,10 @@ func GenerateCurrentInstallStrategy(config *operatorutil.KubeVirtDeploymentConfi
 
 	strategy := &Strategy{}
 
+	if config.CreateNamespaceEnabled() {
+		strategy.namespaces = append(strategy.namespaces, components.NewNamespace(config.GetNamespace()))
+	}
+
 	functions := []func() (*extv1.CustomResourceDefinition, error){
 		components.NewVirtualMachineInstanceCrd, components.NewPresetCrd, components.NewReplicaSetCrd,
 		components.NewVirtualMachineCrd, components.NewVirtualMachineInstanceMigrationCrd,
@@ -405,11 +1641,17 @@ func GenerateCurrentInstallStrategy(config *operatorutil.KubeVirtDeploymentConfi
 		components.NewVirtualMachineClusterPreferenceCrd, components.NewVirtualMachineExportCrd,
 		components.NewVirtualMachineCloneCrd,
 	}
+	crdShortNames := config.GetCRDShortNames()
+	crdCategories := config.GetCRDCategories()
 	for _, f := range functions {
 		crd, err := f()
 		if err != nil {
 			return nil, err
 		}
+		if config.ExtendedPrinterColumnsEnabled() {
+			components.AddExtendedPrinterColumns(crd)
+		}
+		components.AddCRDNames(crd, crdShortNames[crd.Name], crdCategories[crd.Name])
 		strategy.crds = append(strategy.crds, crd)
 	}
 
@@ -493,13 +1735,18 @@ func GenerateCurrentInstallStrategy(config *operatorutil.KubeVirtDeploymentConfi
 	strategy.services = append(strategy.services, components.NewApiServerService(config.GetNamespace()))
 	strategy.services = append(strategy.services, components.NewOperatorWebhookService(operatorNamespace))
 	strategy.services = append(strategy.services, components.NewExportProxyService(config.GetNamespace()))
-	apiDeployment, err := components.NewApiServerDeployment(config.GetNamespace(), config.GetImageRegistry(), config.GetImagePrefix(), config.GetApiVersion(), productName, productVersion, productComponent, config.VirtApiImage, config.GetImagePullPolicy(), config.GetImagePullSecrets(), config.GetVerbosity(), config.GetExtraEnv())
+
+	arch := config.GetArchitecture()
+	apiDeployment, err := components.NewApiServerDeployment(config.GetNamespace(), config.GetImageRegistry(), config.GetImagePrefix(), components.AddArchitectureSuffix(config.GetApiVersion(), arch), productName, productVersion, productComponent, config.VirtApiImage, config.GetImagePullPolicy(), config.GetImagePullSecrets(), config.GetVerbosity(), config.GetExtraEnv())
 	if err != nil {
 		return nil, fmt.Errorf("error generating virt-apiserver deployment %v", err)
 	}
+	if config.ApiMetricsTLSEnabled() {
+		components.EnableApiMetricsTLS(apiDeployment)
+	}
 	strategy.deployments = append(strategy.deployments, apiDeployment)
 
-	controller, err := components.NewControllerDeployment(config.GetNamespace(), config.GetImageRegistry(), config.GetImagePrefix(), config.GetControllerVersion(), config.GetLauncherVersion(), config.GetExportServerVersion(), productName, productVersion, productComponent, config.VirtControllerImage, config.VirtLauncherImage, config.VirtExportServerImage, config.GetImagePullPolicy(), config.GetImagePullSecrets(), config.GetVerbosity(), config.GetExtraEnv())
+	controller, err := components.NewControllerDeployment(config.GetNamespace(), config.GetImageRegistry(), config.GetImagePrefix(), components.AddArchitectureSuffix(config.GetControllerVersion(), arch), components.AddArchitectureSuffix(config.GetLauncherVersion(), arch), components.AddArchitectureSuffix(config.GetExportServerVersion(), arch), productName, productVersion, productComponent, config.VirtControllerImage, config.VirtLauncherImage, config.VirtExportServerImage, config.GetImagePullPolicy(), config.GetImagePullSecrets(), config.GetVerbosity(), config.GetExtraEnv())
 	if err != nil {
 		return nil, fmt.Errorf("error generating virt-controller deployment %v", err)
 	}
@@ -507,25 +1754,124 @@ func GenerateCurrentInstallStrategy(config *operatorutil.KubeVirtDeploymentConfi
 
 	strategy.configMaps = append(strategy.configMaps, components.NewCAConfigMaps(operatorNamespace)...)
 
-	exportProxyDeployment, err := components.NewExportProxyDeployment(config.GetNamespace(), config.GetImageRegistry(), config.GetImagePrefix(), config.GetExportProxyVersion(), productName, productVersion, productComponent, config.VirtExportProxyImage, config.GetImagePullPolicy(), config.GetImagePullSecrets(), config.GetVerbosity(), config.GetExtraEnv())
+	exportProxyDeployment, err := components.NewExportProxyDeployment(config.GetNamespace(), config.GetImageRegistry(), config.GetImagePrefix(), components.AddArchitectureSuffix(config.GetExportProxyVersion(), arch), productName, productVersion, productComponent, config.VirtExportProxyImage, config.GetImagePullPolicy(), config.GetImagePullSecrets(), config.GetVerbosity(), config.GetExtraEnv())
 	if err != nil {
 		return nil, fmt.Errorf("error generating export proxy deployment %v", err)
 	}
 	strategy.deployments = append(strategy.deployments, exportProxyDeployment)
 
-	handler, err := components.NewHandlerDaemonSet(config.GetNamespace(), config.GetImageRegistry(), config.GetImagePrefix(), config.GetHandlerVersion(), config.GetLauncherVersion(), config.GetPrHelperVersion(), productName, productVersion, productComponent, config.VirtHandlerImage, config.VirtLauncherImage, config.PrHelperImage, config.GetImagePullPolicy(), config.GetImagePullSecrets(), config.GetMigrationNetwork(), config.GetVerbosity(), config.GetExtraEnv(), config.PersistentReservationEnabled())
+	handler, err := components.NewHandlerDaemonSet(config.GetNamespace(), config.GetImageRegistry(), config.GetImagePrefix(), components.AddArchitectureSuffix(config.GetHandlerVersion(), arch), components.AddArchitectureSuffix(config.GetLauncherVersion(), arch), components.AddArchitectureSuffix(config.GetPrHelperVersion(), arch), productName, productVersion, productComponent, config.VirtHandlerImage, config.VirtLauncherImage, config.PrHelperImage, config.GetImagePullPolicy(), config.GetImagePullSecrets(), config.GetMigrationNetwork(), config.GetVerbosity(), config.GetExtraEnv(), config.PersistentReservationEnabled())
 	if err != nil {
 		return nil, fmt.Errorf("error generating virt-handler deployment %v", err)
 	}
+	handler.Spec.UpdateStrategy.Type = config.GetHandlerUpdateStrategyType()
+	rollingUpdateDaemonSet, err := config.GetHandlerRollingUpdate()
+	if err != nil {
+		return nil, err
+	}
+	if rollingUpdateDaemonSet != nil && handler.Spec.UpdateStrategy.Type == appsv1.RollingUpdateDaemonSetStrategyType {
+		handler.Spec.UpdateStrategy.RollingUpdate = rollingUpdateDaemonSet
+	}
+	if err := components.AddHandlerExtraVolumes(handler, config.GetHandlerExtraVolumes(), config.GetHandlerExtraVolumeMounts()); err != nil {
+		return nil, err
+	}
+	if config.HandlerHostNetworkEnabled() {
+		// keep resolving cluster services from the node network
+		handler.Spec.Template.Spec.HostNetwork = true
+		handler.Spec.Template.Spec.DNSPolicy = corev1.DNSClusterFirstWithHostNet
+	}
 
 	strategy.daemonSets = append(strategy.daemonSets, handler)
-	strategy.sccs = append(strategy.sccs, components.GetAllSCC(config.GetNamespace())...)
+
+	rollingUpdate, err := config.GetDeploymentRollingUpdate()
+	if err != nil {
+		return nil, err
+	}
+	for _, deployment := range strategy.deployments {
+		if rollingUpdate != nil {
+			deployment.Spec.Strategy = appsv1.DeploymentStrategy{
+				Type:          appsv1.RollingUpdateDeploymentStrategyType,
+				RollingUpdate: rollingUpdate.DeepCopy(),
+			}
+		}
+		components.InjectArchitectureAffinity(&deployment.Spec.Template.Spec, arch)
+		components.InjectGracefulShutdown(&deployment.Spec.Template.Spec, config.GetDeploymentPreStopCommand(), config.GetDeploymentTerminationGracePeriod())
+	}
+	for _, daemonSet := range strategy.daemonSets {
+		components.InjectArchitectureAffinity(&daemonSet.Spec.Template.Spec, arch)
+	}
+
+	strategy.sccs = append(strategy.sccs, components.GetAllSCC(config.GetNamespace(), config.HandlerHostNetworkEnabled())...)
 	strategy.apiServices = components.NewVirtAPIAPIServices(config.GetNamespace())
 	strategy.certificateSecrets = components.NewCertSecrets(config.GetNamespace(), operatorNamespace)
+	if config.ApiMetricsTLSEnabled() {
+		strategy.certificateSecrets = append(strategy.certificateSecrets, components.NewApiMetricsCertSecret(config.GetNamespace()))
+	}
 	strategy.certificateSecrets = append(strategy.certificateSecrets, components.NewCACertSecrets(operatorNamespace)...)
 	strategy.configMaps = append(strategy.configMaps, components.NewCAConfigMaps(operatorNamespace)...)
+	if storageClassDefaults := components.NewStorageClassDefaultsConfigMap(operatorNamespace, config.GetStorageClassDefaults()); storageClassDefaults != nil {
+		strategy.configMaps = append(strategy.configMaps, storageClassDefaults)
+	}
+	strategy.configMaps = append(strategy.configMaps, components.NewBinaryDataConfigMaps(operatorNamespace, config.GetConfigMapBinaryData())...)
 	strategy.routes = append(strategy.routes, components.GetAllRoutes(operatorNamespace)...)
 
+	if err := strategy.overrideServiceAccountNames(config.GetNamespace(), config.GetServiceAccountNames()); err != nil {
+		return nil, err
+	}
+
+	if err := strategy.overrideContainers(config.GetNamespace(), config.GetContainerOverrides()); err != nil {
+		return nil, err
+	}
+
+	if err := strategy.setSessionAffinity(config.GetNamespace(), config.GetServiceSessionAffinity()); err != nil {
+		return nil, err
+	}
+
+	if err := strategy.addReadinessGates(config.GetNamespace(), config.GetReadinessGates()); err != nil {
+		return nil, err
+	}
+
+	if err := strategy.addStartupProbes(config.GetNamespace(), config.GetStartupProbes()); err != nil {
+		return nil, err
+	}
+
+	if err := strategy.applyObjectPatches(config.GetObjectPatches()); err != nil {
+		return nil, err
+	}
+
+	// after the patches, which may add references to config maps
+	if err := strategy.addConfigMapChecksums(); err != nil {
+		return nil, err
+	}
+
+	// after the patches, which may drop the labels the ServiceMonitor scrapes the metrics service by
+	if RepairMetricsServiceLabels(strategy) {
+		log.Log.Warningf("restored the scrape labels of the metrics service %s", components.PrometheusServiceName)
+	}
+
+	// after the patches, which may change the type of a service
+	loadBalancerAnnotations := config.GetLoadBalancerServiceAnnotations()
+	for _, service := range strategy.services {
+		components.AddLoadBalancerAnnotations(service, loadBalancerAnnotations)
+	}
+
+	if rbacAnnotations := config.GetRBACAnnotations(); len(rbacAnnotations) > 0 {
+		strategy.addRBACAnnotations(rbacAnnotations)
+	}
+
+	// fall back to the KubeVirt version if the product version is unset or invalid
+	versionLabel := productVersion
+	if versionLabel == "" {
+		versionLabel = config.GetKubeVirtVersion()
+	}
+	if operatorutil.IsValidLabel(versionLabel) {
+		strategy.addVersionLabel(versionLabel)
+	}
+
+	if finalizer := config.GetObjectFinalizer(); finalizer != "" {
+		strategy.addObjectFinalizer(finalizer)
+	}
+
 	return strategy, nil
 }
 
@@ -564,7 +1910,49 @@ func getManifests(configMap *corev1.ConfigMap) (string, error) {
 	return manifests, nil
 }
 
-func LoadInstallStrategyFromCache(stores util.Stores, config *operatorutil.KubeVirtDeploymentConfig) (*Strategy, error) {
+// LoadOption configures how an install strategy is loaded
+type LoadOption func(*loadOptions)
+
+type loadOptions struct {
+	requireManagedByLabel bool
+}
+
+// RequireManagedByLabel makes loading fail if a config map the strategy is read from is not labeled as managed by the
+// operator. The objects of the strategy itself carry no such label, it is stripped when the strategy is dumped and
+// injected again when the objects are applied.
+func RequireManagedByLabel() LoadOption {
+	return func(o *loadOptions) {
+		o.requireManagedByLabel = true
+	}
+}
+
+func newLoadOptions(opts []LoadOption) *loadOptions {
+	options := &loadOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// checkConfigMaps returns an error naming the config maps which are not labeled as managed by the operator, if the
+// label is required
+func (o *loadOptions) checkConfigMaps(configMaps ...*corev1.ConfigMap) error {
+	if !o.requireManagedByLabel {
+		return nil
+	}
+	var unmanaged []string
+	for _, configMap := range configMaps {
+		if !util.IsManagedByOperator(configMap.Labels) {
+			unmanaged = append(unmanaged, fmt.Sprintf("%s/%s", configMap.Namespace, configMap.Name))
+		}
+	}
+	if len(unmanaged) > 0 {
+		return fmt.Errorf("install strategy config maps without the %s=%s label: %s", v1.ManagedByLabel, v1.ManagedByLabelOperatorValue, strings.Join(unmanaged, ", "))
+	}
+	return nil
+}
+
+func LoadInstallStrategyFromCache(stores util.Stores, config *operatorutil.KubeVirtDeploymentConfig, opts ...LoadOption) (*Strategy, error) {
 	var matchingConfigMaps []*corev1.ConfigMap
 
 	for _, obj := range stores.InstallStrategyConfigMapCache.List() {
@@ -593,7 +1981,12 @@ func LoadInstallStrategyFromCache(stores util.Stores, config *operatorutil.KubeV
 		return nil, fmt.Errorf("no install strategy configmap found for version %s with registry %s", config.GetKubeVirtVersion(), config.GetImageRegistry())
 	}
 
-	manifests, err := getManifests(mostRecentConfigMap(matchingConfigMaps))
+	configMap := mostRecentConfigMap(matchingConfigMaps)
+	if err := newLoadOptions(opts).checkConfigMaps(configMap); err != nil {
+		return nil, err
+	}
+
+	manifests, err := getManifests(configMap)
 	if err != nil {
 		return nil, err
 	}
@@ -740,6 +2133,12 @@ func loadInstallStrategyFromBytes(data string) (*Strategy, error) {
 				return nil, err
 			}
 			strategy.prometheusRules = append(strategy.prometheusRules, pr)
+		case "Namespace":
+			namespace := &corev1.Namespace{}
+			if err := yaml.Unmarshal([]byte(entry), &namespace); err != nil {
+				return nil, err
+			}
+			strategy.namespaces = append(strategy.namespaces, namespace)
 		case "ConfigMap":
 			configMap := &corev1.ConfigMap{}
 			if err := yaml.Unmarshal([]byte(entry), &configMap); err != nil {
@@ -758,6 +2157,47 @@ func loadInstallStrategyFromBytes(data string) (*Strategy, error) {
 		}
 		log.Log.Infof("%s loaded", obj.Kind)
 	}
+
+	return strategy, nil
+}
+
+// loadInstallStrategyFromConfigMaps loads a strategy which is split across several config maps, e.g. because it does
+// not fit into a single one. An object must not be part of more than one of the config maps.
+func loadInstallStrategyFromConfigMaps(configMaps []*corev1.ConfigMap, opts ...LoadOption) (*Strategy, error) {
+	if err := newLoadOptions(opts).checkConfigMaps(configMaps...); err != nil {
+		return nil, err
+	}
+
+	strategy := &Strategy{}
+	loadedFrom := map[objectIdentity]string{}
+	var duplicates []string
+	for _, configMap := range configMaps {
+		manifests, err := getManifests(configMap)
+		if err != nil {
+			return nil, err
+		}
+		part, err := loadInstallStrategyFromBytes(manifests)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, o := range part.objects() {
+			id := o.identity()
+			if previous, exists := loadedFrom[id]; exists && previous != configMap.Name {
+				name := id.Name
+				if id.Namespace != "" {
+					name = id.Namespace + "/" + name
+				}
+				duplicates = append(duplicates, fmt.Sprintf("%s %s in %s and %s", id.Kind, name, previous, configMap.Name))
+				continue
+			}
+			loadedFrom[id] = configMap.Name
+			strategy.addObject(o.obj)
+		}
+	}
+	if len(duplicates) > 0 {
+		return nil, fmt.Errorf("install strategy config maps contain duplicate objects: %s", strings.Join(duplicates, ", "))
+	}
 	return strategy, nil
 }
 
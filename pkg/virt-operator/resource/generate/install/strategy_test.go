@@ -20,9 +20,12 @@
 package install
 
 import (
+	"context"
+	"fmt"
 	"strings"
 
 	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/tools/cache"
@@ -35,11 +38,15 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/rand"
 
 	v1 "kubevirt.io/api/core/v1"
 
 	//"kubevirt.io/kubevirt/pkg/virt-operator/resource/apply"
+	"kubevirt.io/kubevirt/pkg/virt-operator/resource/generate/components"
 	"kubevirt.io/kubevirt/pkg/virt-operator/util"
 )
 
@@ -229,6 +236,1233 @@ var _ = Describe("Install Strategy", func() {
 		})
 	})
 
+	Context("equality", func() {
+		var strategy, other *Strategy
+
+		BeforeEach(func() {
+			var err error
+			strategy, err = GenerateCurrentInstallStrategy(config, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+			other, err = GenerateCurrentInstallStrategy(config, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should consider equally generated strategies equal", func() {
+			Expect(StrategyEqual(strategy, other)).To(BeTrue())
+		})
+
+		It("should ignore server-set metadata and status", func() {
+			other.deployments[0].ResourceVersion = "1234"
+			other.deployments[0].UID = "some-uid"
+			other.deployments[0].Generation = 7
+			other.deployments[0].Status.ReadyReplicas = 2
+			Expect(StrategyEqual(strategy, other)).To(BeTrue())
+		})
+
+		It("should detect an extra object", func() {
+			other.serviceAccounts = append(other.serviceAccounts, newSA(namespace, "extra"))
+			Expect(StrategyEqual(strategy, other)).To(BeFalse())
+			Expect(StrategyEqual(other, strategy)).To(BeFalse())
+		})
+
+		It("should detect a modified spec", func() {
+			other.deployments[0].Spec.Template.Spec.Containers[0].Image = "modified"
+			Expect(StrategyEqual(strategy, other)).To(BeFalse())
+		})
+	})
+
+	Context("redacting secrets", func() {
+		var strategy *Strategy
+
+		BeforeEach(func() {
+			strategy = &Strategy{
+				certificateSecrets: []*corev1.Secret{{
+					ObjectMeta: metav1.ObjectMeta{Name: "certs", Namespace: namespace},
+					Data: map[string][]byte{
+						"tls.key": []byte("private"),
+						"tls.crt": []byte("public"),
+					},
+				}},
+				configMaps: []*corev1.ConfigMap{{
+					ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: namespace},
+					Data: map[string]string{
+						"api-token": "private",
+						"ca-bundle": "public",
+					},
+				}},
+			}
+		})
+
+		It("should redact sensitive values with the default patterns and leave the original untouched", func() {
+			redacted, err := RedactSecrets(strategy)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(redacted.CertificateSecrets()[0].Data).To(Equal(map[string][]byte{
+				"tls.key": []byte("***"),
+				"tls.crt": []byte("public"),
+			}))
+			Expect(redacted.ConfigMaps()[0].Data).To(Equal(map[string]string{
+				"api-token": "***",
+				"ca-bundle": "public",
+			}))
+
+			Expect(strategy.CertificateSecrets()[0].Data["tls.key"]).To(Equal([]byte("private")))
+			Expect(strategy.ConfigMaps()[0].Data["api-token"]).To(Equal("private"))
+		})
+
+		It("should redact with custom patterns", func() {
+			redacted, err := RedactSecrets(strategy, `^ca-bundle$`)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(redacted.CertificateSecrets()[0].Data["tls.key"]).To(Equal([]byte("private")))
+			Expect(redacted.ConfigMaps()[0].Data).To(Equal(map[string]string{
+				"api-token": "private",
+				"ca-bundle": "***",
+			}))
+		})
+
+		It("should reject invalid patterns", func() {
+			_, err := RedactSecrets(strategy, `(`)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("namespaces", func() {
+		It("should report the install, operator and cross-namespace subject namespaces", func() {
+			strategy, err := GenerateCurrentInstallStrategy(config, "openshift-monitoring", "operator-namespace")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(strategy.Namespaces()).To(Equal([]string{namespace, "openshift-monitoring", "operator-namespace"}))
+		})
+
+		It("should not report a monitoring namespace if monitoring is not available", func() {
+			strategy, err := GenerateCurrentInstallStrategy(config, "", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(strategy.Namespaces()).To(Equal([]string{namespace}))
+		})
+
+		It("should not generate the install namespace by default", func() {
+			strategy, err := GenerateCurrentInstallStrategy(config, "", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(strategy.NamespaceObjects()).To(BeEmpty())
+		})
+
+		It("should generate the install namespace first when requested", func() {
+			namespaceConfig := getConfig("fake-registry", "v9.9.9")
+			namespaceConfig.AdditionalProperties[util.AdditionalPropertiesCreateNamespace] = ""
+
+			strategy, err := GenerateCurrentInstallStrategy(namespaceConfig, "", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(strategy.NamespaceObjects()).To(HaveLen(1))
+			Expect(strategy.NamespaceObjects()[0].Name).To(Equal(namespace))
+			Expect(strategy.NamespaceObjects()[0].Labels).To(HaveKeyWithValue(v1.ManagedByLabel, v1.ManagedByLabelOperatorValue))
+			Expect(strategy.objects()[0].kind).To(Equal("Namespace"))
+
+			dumped := string(dumpInstallStrategyToBytes(strategy))
+			Expect(strings.TrimLeft(dumped, "-\n")).To(HavePrefix("apiVersion: v1\nkind: Namespace\n"))
+
+			newStrategy, err := loadInstallStrategyFromBytes(dumped)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(newStrategy.NamespaceObjects()).To(HaveLen(1))
+			Expect(newStrategy.NamespaceObjects()[0].Name).To(Equal(namespace))
+		})
+	})
+
+	Context("required apply permissions", func() {
+		It("should cover every kind of the strategy", func() {
+			strategy, err := GenerateCurrentInstallStrategy(config, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			rules := RequiredApplyPermissions(strategy)
+			Expect(rules).To(ContainElement(rbacv1.PolicyRule{
+				APIGroups: []string{"apiextensions.k8s.io"},
+				Resources: []string{"customresourcedefinitions"},
+				Verbs:     []string{"get", "list", "watch", "create", "update", "patch", "delete"},
+			}))
+			Expect(rules).To(ContainElement(rbacv1.PolicyRule{
+				APIGroups: []string{"apps"},
+				Resources: []string{"deployments"},
+				Verbs:     []string{"get", "list", "watch", "create", "update", "patch", "delete"},
+			}))
+			Expect(rules).To(ContainElement(HaveField("Resources", []string{"services"})))
+			Expect(rules).To(ContainElement(HaveField("Resources", []string{"routes"})))
+		})
+	})
+
+	Context("CRD versions", func() {
+		It("should report the served versions of every CRD", func() {
+			strategy, err := GenerateCurrentInstallStrategy(config, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			versions := strategy.CRDVersions()
+			Expect(versions).To(HaveLen(len(strategy.CRDs())))
+			Expect(versions).To(HaveKeyWithValue("virtualmachineinstances.kubevirt.io", []string{"v1", "v1alpha3"}))
+		})
+	})
+
+	Context("images by component", func() {
+		It("should report the image of every component", func() {
+			strategy, err := GenerateCurrentInstallStrategy(config, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			images := strategy.ImagesByComponent()
+			Expect(images).To(HaveKeyWithValue("virt-api", "fake-registry/virt-api:v9.9.9"))
+			Expect(images).To(HaveKeyWithValue("virt-controller", "fake-registry/virt-controller:v9.9.9"))
+			Expect(images).To(HaveKeyWithValue("virt-handler", "fake-registry/virt-handler:v9.9.9"))
+			Expect(images).To(HaveKeyWithValue("virt-launcher", "fake-registry/virt-launcher:v9.9.9"))
+		})
+	})
+
+	Context("component objects", func() {
+		It("should return the objects of virt-api", func() {
+			strategy, err := GenerateCurrentInstallStrategy(config, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			var names []string
+			for _, obj := range strategy.ObjectsForComponent(components.VirtAPIName) {
+				metaObj, err := meta.Accessor(obj)
+				Expect(err).ToNot(HaveOccurred())
+				names = append(names, obj.GetObjectKind().GroupVersionKind().Kind+"/"+metaObj.GetName())
+			}
+			Expect(names).To(ContainElements(
+				"Service/"+components.VirtApiServiceName,
+				"Deployment/"+components.VirtAPIName,
+				"ServiceAccount/"+components.ApiServiceAccountName,
+			))
+			Expect(names).ToNot(ContainElement("Deployment/" + components.VirtControllerName))
+			Expect(names).ToNot(ContainElement("ServiceAccount/" + components.ControllerServiceAccountName))
+		})
+	})
+
+	Context("webhooks", func() {
+		It("should report the VMI validating webhook endpoint", func() {
+			strategy, err := GenerateCurrentInstallStrategy(config, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			webhooks := strategy.Webhooks()
+			Expect(webhooks).To(ContainElement(WebhookEndpoint{
+				Kind:             "ValidatingWebhookConfiguration",
+				Configuration:    components.VirtAPIValidatingWebhookName,
+				Webhook:          "virtualmachineinstances-create-validator.kubevirt.io",
+				ServiceNamespace: namespace,
+				ServiceName:      components.VirtApiServiceName,
+				Path:             components.VMICreateValidatePath,
+				Port:             443,
+			}))
+
+			var mutating int
+			for _, webhook := range webhooks {
+				Expect(webhook.ServiceName).ToNot(BeEmpty(), webhook.Webhook)
+				if webhook.Kind == "MutatingWebhookConfiguration" {
+					mutating++
+				}
+			}
+			Expect(mutating).To(BeNumerically(">", 0))
+		})
+	})
+
+	Context("resource footprint", func() {
+		podSpec := func(name, cpu, memory string) corev1.PodSpec {
+			return corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name: name,
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse(cpu),
+							corev1.ResourceMemory: resource.MustParse(memory),
+						},
+					},
+				}},
+			}
+		}
+
+		It("should sum requests across Deployment replicas and per node for DaemonSets", func() {
+			replicas := int32(2)
+			handlerSpec := podSpec("virt-handler", "10m", "325Mi")
+			handlerSpec.InitContainers = []corev1.Container{{
+				Name: "virt-launcher",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("50m")},
+				},
+			}}
+			strategy := &Strategy{
+				deployments: []*appsv1.Deployment{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "virt-api"},
+						Spec: appsv1.DeploymentSpec{
+							Replicas: &replicas,
+							Template: corev1.PodTemplateSpec{Spec: podSpec("virt-api", "5m", "500Mi")},
+						},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "virt-operator"},
+						Spec: appsv1.DeploymentSpec{
+							Template: corev1.PodTemplateSpec{Spec: podSpec("virt-operator", "10m", "450Mi")},
+						},
+					},
+				},
+				daemonSets: []*appsv1.DaemonSet{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "virt-handler"},
+						Spec: appsv1.DaemonSetSpec{
+							Template: corev1.PodTemplateSpec{Spec: handlerSpec},
+						},
+					},
+				},
+			}
+
+			footprint := strategy.ResourceFootprint()
+			Expect(footprint.Requests.Cpu().Equal(resource.MustParse("20m"))).To(BeTrue(), footprint.Requests.Cpu().String())
+			Expect(footprint.Requests.Memory().Equal(resource.MustParse("1450Mi"))).To(BeTrue(), footprint.Requests.Memory().String())
+			Expect(footprint.Limits).To(BeEmpty())
+			// the init container requests more CPU than the containers of the pod
+			Expect(footprint.PerNodeRequests.Cpu().Equal(resource.MustParse("50m"))).To(BeTrue(), footprint.PerNodeRequests.Cpu().String())
+			Expect(footprint.PerNodeRequests.Memory().Equal(resource.MustParse("325Mi"))).To(BeTrue(), footprint.PerNodeRequests.Memory().String())
+		})
+	})
+
+	Context("readiness gates", func() {
+		It("should add the readiness gates to the pods of virt-api", func() {
+			gatesConfig := getConfig("fake-registry", "v9.9.9")
+			gatesConfig.AdditionalProperties[util.AdditionalPropertiesReadinessGates] = `{"virt-api": ["example.org/load-balancer-ready", "example.org/load-balancer-ready"]}`
+
+			strategy, err := GenerateCurrentInstallStrategy(gatesConfig, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			for _, deployment := range strategy.Deployments() {
+				if deployment.Name == components.VirtAPIName {
+					Expect(deployment.Spec.Template.Spec.ReadinessGates).To(ConsistOf(corev1.PodReadinessGate{ConditionType: "example.org/load-balancer-ready"}))
+				} else {
+					Expect(deployment.Spec.Template.Spec.ReadinessGates).To(BeEmpty(), deployment.Name)
+				}
+			}
+		})
+
+		It("should fail for an unknown deployment", func() {
+			gatesConfig := getConfig("fake-registry", "v9.9.9")
+			gatesConfig.AdditionalProperties[util.AdditionalPropertiesReadinessGates] = `{"virt-missing": ["example.org/ready"]}`
+
+			_, err := GenerateCurrentInstallStrategy(gatesConfig, "openshift-monitoring", namespace)
+			Expect(err).To(MatchError("readiness gates for unknown deployment virt-missing"))
+		})
+	})
+
+	Context("object fingerprint", func() {
+		It("should only change with the content of the object", func() {
+			service := components.NewPrometheusService(namespace)
+			equal := service.DeepCopy()
+			equal.TypeMeta = metav1.TypeMeta{}
+			equal.UID = "2d8e35a6-0c8e-4c2b-9a5a-7e5d2c4a1b3f"
+			equal.ResourceVersion = "1234"
+			equal.CreationTimestamp = metav1.Now()
+			equal.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}}
+
+			fingerprint, err := ObjectFingerprint(service)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fingerprint).ToNot(BeEmpty())
+			Expect(ObjectFingerprint(equal)).To(Equal(fingerprint))
+
+			modified := service.DeepCopy()
+			modified.Spec.Ports[0].Port++
+			Expect(ObjectFingerprint(modified)).ToNot(Equal(fingerprint))
+		})
+
+		It("should ignore operator-injected metadata and API server defaults of a service", func() {
+			service := components.NewPrometheusService(namespace)
+			service.Spec.SessionAffinity = ""
+			service.Spec.Ports[0].Protocol = ""
+			fingerprint, err := ObjectFingerprint(service)
+			Expect(err).ToNot(HaveOccurred())
+
+			deployed := service.DeepCopy()
+			deployed.Labels[v1.AppVersionLabel] = "v9.9.9"
+			deployed.Labels[v1.ManagedByLabel] = v1.ManagedByLabelOperatorValue
+			deployed.Annotations = map[string]string{
+				v1.InstallStrategyVersionAnnotation:  "v9.9.9",
+				v1.InstallStrategyRegistryAnnotation: "fake-registry",
+				v1.KubeVirtGenerationAnnotation:      "3",
+			}
+			deployed.Spec.ClusterIP = "10.0.0.5"
+			deployed.Spec.ClusterIPs = []string{"10.0.0.5"}
+			deployed.Spec.IPFamilies = []corev1.IPFamily{corev1.IPv4Protocol}
+			singleStack := corev1.IPFamilyPolicySingleStack
+			deployed.Spec.IPFamilyPolicy = &singleStack
+			deployed.Spec.SessionAffinity = corev1.ServiceAffinityNone
+			deployed.Spec.Ports[0].Protocol = corev1.ProtocolTCP
+			Expect(ObjectFingerprint(deployed)).To(Equal(fingerprint))
+
+			deployed.Spec.SessionAffinity = corev1.ServiceAffinityClientIP
+			Expect(ObjectFingerprint(deployed)).ToNot(Equal(fingerprint))
+		})
+
+		It("should ignore API server defaults of a deployment", func() {
+			strategy, err := GenerateCurrentInstallStrategy(getConfig("fake-registry", "v9.9.9"), "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+			deployment := strategy.ApiDeployments()[0]
+			fingerprint, err := ObjectFingerprint(deployment)
+			Expect(err).ToNot(HaveOccurred())
+
+			revisionHistoryLimit := int32(10)
+			progressDeadlineSeconds := int32(600)
+			terminationGracePeriodSeconds := int64(corev1.DefaultTerminationGracePeriodSeconds)
+			deployed := deployment.DeepCopy()
+			deployed.Annotations = map[string]string{"deployment.kubernetes.io/revision": "2"}
+			deployed.Spec.RevisionHistoryLimit = &revisionHistoryLimit
+			deployed.Spec.ProgressDeadlineSeconds = &progressDeadlineSeconds
+			deployed.Spec.Template.Labels[v1.AppComponentLabel] = v1.AppComponent
+			deployed.Spec.Template.Spec.DNSPolicy = corev1.DNSClusterFirst
+			deployed.Spec.Template.Spec.RestartPolicy = corev1.RestartPolicyAlways
+			deployed.Spec.Template.Spec.SchedulerName = corev1.DefaultSchedulerName
+			deployed.Spec.Template.Spec.TerminationGracePeriodSeconds = &terminationGracePeriodSeconds
+			for i := range deployed.Spec.Template.Spec.Containers {
+				deployed.Spec.Template.Spec.Containers[i].TerminationMessagePath = corev1.TerminationMessagePathDefault
+				deployed.Spec.Template.Spec.Containers[i].TerminationMessagePolicy = corev1.TerminationMessageReadFile
+			}
+			Expect(ObjectFingerprint(deployed)).To(Equal(fingerprint))
+
+			terminationGracePeriodSeconds = 5
+			Expect(ObjectFingerprint(deployed)).ToNot(Equal(fingerprint))
+		})
+
+		It("should fail for an object which can't be canonicalized", func() {
+			var service *corev1.Service
+			_, err := ObjectFingerprint(service)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("startup probes", func() {
+		It("should set the startup probe on the virt-api container", func() {
+			probesConfig := getConfig("fake-registry", "v9.9.9")
+			probesConfig.AdditionalProperties[util.AdditionalPropertiesStartupProbes] = `{"virt-api": {"failureThreshold": 30, "periodSeconds": 10}}`
+
+			strategy, err := GenerateCurrentInstallStrategy(probesConfig, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			for _, deployment := range strategy.Deployments() {
+				for _, container := range deployment.Spec.Template.Spec.Containers {
+					if container.Name != components.VirtAPIName {
+						Expect(container.StartupProbe).To(BeNil(), container.Name)
+						continue
+					}
+					Expect(container.StartupProbe).ToNot(BeNil())
+					Expect(container.StartupProbe.FailureThreshold).To(Equal(int32(30)))
+					Expect(container.StartupProbe.PeriodSeconds).To(Equal(int32(10)))
+					// the handler is taken from the readiness probe
+					Expect(container.StartupProbe.ProbeHandler).To(Equal(container.ReadinessProbe.ProbeHandler))
+				}
+			}
+		})
+
+		It("should keep an explicit handler", func() {
+			probesConfig := getConfig("fake-registry", "v9.9.9")
+			probesConfig.AdditionalProperties[util.AdditionalPropertiesStartupProbes] = `{"virt-handler": {"exec": {"command": ["true"]}}}`
+
+			strategy, err := GenerateCurrentInstallStrategy(probesConfig, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			handler := strategy.DaemonSets()[0]
+			Expect(handler.Spec.Template.Spec.Containers[0].StartupProbe.Exec.Command).To(Equal([]string{"true"}))
+		})
+
+		It("should fail for an unknown component", func() {
+			probesConfig := getConfig("fake-registry", "v9.9.9")
+			probesConfig.AdditionalProperties[util.AdditionalPropertiesStartupProbes] = `{"virt-missing": {"periodSeconds": 10}}`
+
+			_, err := GenerateCurrentInstallStrategy(probesConfig, "openshift-monitoring", namespace)
+			Expect(err).To(MatchError("startup probe for unknown component virt-missing"))
+		})
+	})
+
+	Context("virt-handler host network", func() {
+		It("should use the pod network by default", func() {
+			strategy, err := GenerateCurrentInstallStrategy(config, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(strategy.DaemonSets()).To(HaveLen(1))
+			podSpec := strategy.DaemonSets()[0].Spec.Template.Spec
+			Expect(podSpec.HostNetwork).To(BeFalse())
+			Expect(podSpec.DNSPolicy).To(BeEmpty())
+		})
+
+		It("should use the host network with the matching DNS policy when requested", func() {
+			hostNetworkConfig := getConfig("fake-registry", "v9.9.9")
+			hostNetworkConfig.AdditionalProperties[util.AdditionalPropertiesHandlerHostNetwork] = ""
+
+			strategy, err := GenerateCurrentInstallStrategy(hostNetworkConfig, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(strategy.DaemonSets()).To(HaveLen(1))
+			podSpec := strategy.DaemonSets()[0].Spec.Template.Spec
+			Expect(podSpec.HostNetwork).To(BeTrue())
+			Expect(podSpec.DNSPolicy).To(Equal(corev1.DNSClusterFirstWithHostNet))
+			for _, deployment := range strategy.Deployments() {
+				Expect(deployment.Spec.Template.Spec.HostNetwork).To(BeFalse(), deployment.Name)
+			}
+			for _, scc := range strategy.SCCs() {
+				Expect(scc.AllowHostNetwork).To(Equal(scc.Name == "kubevirt-handler"), scc.Name)
+			}
+		})
+	})
+
+	Context("virt-handler update strategy", func() {
+		It("should default to RollingUpdate", func() {
+			strategy, err := GenerateCurrentInstallStrategy(config, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(strategy.DaemonSets()).To(HaveLen(1))
+			Expect(strategy.DaemonSets()[0].Spec.UpdateStrategy.Type).To(Equal(appsv1.RollingUpdateDaemonSetStrategyType))
+		})
+
+		It("should be OnDelete when requested", func() {
+			onDeleteConfig := getConfig("fake-registry", "v9.9.9")
+			onDeleteConfig.AdditionalProperties[util.AdditionalPropertiesHandlerUpdateStrategy] = string(appsv1.OnDeleteDaemonSetStrategyType)
+
+			strategy, err := GenerateCurrentInstallStrategy(onDeleteConfig, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(strategy.DaemonSets()).To(HaveLen(1))
+			Expect(strategy.DaemonSets()[0].Spec.UpdateStrategy.Type).To(Equal(appsv1.OnDeleteDaemonSetStrategyType))
+		})
+
+		It("should set the requested maxUnavailable", func() {
+			maxUnavailableConfig := getConfig("fake-registry", "v9.9.9")
+			maxUnavailableConfig.AdditionalProperties[util.AdditionalPropertiesHandlerMaxUnavailable] = "10%"
+
+			strategy, err := GenerateCurrentInstallStrategy(maxUnavailableConfig, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			rollingUpdate := strategy.DaemonSets()[0].Spec.UpdateStrategy.RollingUpdate
+			Expect(rollingUpdate).ToNot(BeNil())
+			Expect(*rollingUpdate.MaxUnavailable).To(Equal(intstr.FromString("10%")))
+		})
+
+		DescribeTable("should reject an invalid maxUnavailable", func(value string) {
+			maxUnavailableConfig := getConfig("fake-registry", "v9.9.9")
+			maxUnavailableConfig.AdditionalProperties[util.AdditionalPropertiesHandlerMaxUnavailable] = value
+
+			_, err := GenerateCurrentInstallStrategy(maxUnavailableConfig, "openshift-monitoring", namespace)
+			Expect(err).To(MatchError(ContainSubstring("invalid virt-handler maxUnavailable")))
+		},
+			Entry("of zero nodes", "0"),
+			Entry("above 100%", "101%"),
+			Entry("without a number", "many"),
+		)
+
+		It("should surge without unavailable nodes when a maxSurge is requested", func() {
+			maxSurgeConfig := getConfig("fake-registry", "v9.9.9")
+			maxSurgeConfig.AdditionalProperties[util.AdditionalPropertiesHandlerMaxSurge] = "20%"
+
+			strategy, err := GenerateCurrentInstallStrategy(maxSurgeConfig, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			rollingUpdate := strategy.DaemonSets()[0].Spec.UpdateStrategy.RollingUpdate
+			Expect(rollingUpdate).ToNot(BeNil())
+			Expect(*rollingUpdate.MaxSurge).To(Equal(intstr.FromString("20%")))
+			Expect(*rollingUpdate.MaxUnavailable).To(Equal(intstr.FromInt(0)))
+		})
+
+		It("should reject a maxSurge together with a maxUnavailable", func() {
+			rolloutConfig := getConfig("fake-registry", "v9.9.9")
+			rolloutConfig.AdditionalProperties[util.AdditionalPropertiesHandlerMaxSurge] = "1"
+			rolloutConfig.AdditionalProperties[util.AdditionalPropertiesHandlerMaxUnavailable] = "1"
+
+			_, err := GenerateCurrentInstallStrategy(rolloutConfig, "openshift-monitoring", namespace)
+			Expect(err).To(MatchError("invalid virt-handler rolling update, maxSurge and maxUnavailable must not both be set"))
+		})
+	})
+
+	Context("deployment rolling update", func() {
+		It("should keep the default strategy if not configured", func() {
+			strategy, err := GenerateCurrentInstallStrategy(config, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			for _, deployment := range strategy.Deployments() {
+				Expect(deployment.Spec.Strategy.RollingUpdate).To(BeNil(), deployment.Name)
+			}
+		})
+
+		It("should set the requested percentages on all Deployments", func() {
+			rolloutConfig := getConfig("fake-registry", "v9.9.9")
+			rolloutConfig.AdditionalProperties[util.AdditionalPropertiesDeploymentMaxSurge] = "25%"
+			rolloutConfig.AdditionalProperties[util.AdditionalPropertiesDeploymentMaxUnavailable] = "0"
+
+			strategy, err := GenerateCurrentInstallStrategy(rolloutConfig, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(strategy.Deployments()).ToNot(BeEmpty())
+			for _, deployment := range strategy.Deployments() {
+				Expect(deployment.Spec.Strategy.Type).To(Equal(appsv1.RollingUpdateDeploymentStrategyType))
+				rollingUpdate := deployment.Spec.Strategy.RollingUpdate
+				Expect(rollingUpdate).ToNot(BeNil(), deployment.Name)
+				Expect(*rollingUpdate.MaxSurge).To(Equal(intstr.FromString("25%")))
+				Expect(*rollingUpdate.MaxUnavailable).To(Equal(intstr.FromInt(0)))
+			}
+		})
+
+		DescribeTable("should reject an invalid rolling update", func(maxSurge, maxUnavailable, expected string) {
+			rolloutConfig := getConfig("fake-registry", "v9.9.9")
+			rolloutConfig.AdditionalProperties[util.AdditionalPropertiesDeploymentMaxSurge] = maxSurge
+			rolloutConfig.AdditionalProperties[util.AdditionalPropertiesDeploymentMaxUnavailable] = maxUnavailable
+
+			_, err := GenerateCurrentInstallStrategy(rolloutConfig, "openshift-monitoring", namespace)
+			Expect(err).To(MatchError(ContainSubstring(expected)))
+		},
+			Entry("with a percentage above 100%", "101%", "25%", `invalid deployment maxSurge "101%"`),
+			Entry("with a negative count", "1", "-1", `invalid deployment maxUnavailable "-1"`),
+			Entry("with both at zero", "0%", "0", "must not both be zero"),
+		)
+	})
+
+	Context("object patches", func() {
+		It("should add a sidecar to virt-api", func() {
+			patchConfig := getConfig("fake-registry", "v9.9.9")
+			patchConfig.AdditionalProperties[util.AdditionalPropertiesObjectPatches] = `{"Deployment/virt-api": "[{\"op\":\"add\",\"path\":\"/spec/template/spec/containers/-\",\"value\":{\"name\":\"sidecar\",\"image\":\"sidecar:latest\"}}]"}`
+
+			strategy, err := GenerateCurrentInstallStrategy(patchConfig, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			var apiDeployment *appsv1.Deployment
+			for _, deployment := range strategy.Deployments() {
+				if deployment.Name == "virt-api" {
+					apiDeployment = deployment
+				}
+			}
+			Expect(apiDeployment).ToNot(BeNil())
+			containers := apiDeployment.Spec.Template.Spec.Containers
+			Expect(containers).To(HaveLen(2))
+			Expect(containers[0].Name).To(Equal("virt-api"))
+			Expect(containers[1].Name).To(Equal("sidecar"))
+			Expect(containers[1].Image).To(Equal("sidecar:latest"))
+		})
+
+		It("should fail if the patched object does not exist", func() {
+			patchConfig := getConfig("fake-registry", "v9.9.9")
+			patchConfig.AdditionalProperties[util.AdditionalPropertiesObjectPatches] = `{"Deployment/virt-missing": "[]"}`
+
+			_, err := GenerateCurrentInstallStrategy(patchConfig, "openshift-monitoring", namespace)
+			Expect(err).To(MatchError(ContainSubstring("patch target Deployment/virt-missing does not exist")))
+		})
+	})
+
+	Context("metrics service labels", func() {
+		It("should restore the scrape labels removed by an object patch", func() {
+			patchConfig := getConfig("fake-registry", "v9.9.9")
+			patchConfig.AdditionalProperties[util.AdditionalPropertiesObjectPatches] = fmt.Sprintf(`{"Service/%s": "[{\"op\":\"remove\",\"path\":\"/metadata/labels/prometheus.kubevirt.io\"}]"}`, components.PrometheusServiceName)
+
+			strategy, err := GenerateCurrentInstallStrategy(patchConfig, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ValidateMetricsServiceLabels(strategy)).To(Succeed())
+		})
+	})
+
+	Context("load balancer service annotations", func() {
+		It("should be added to services patched to type LoadBalancer", func() {
+			lbConfig := getConfig("fake-registry", "v9.9.9")
+			lbConfig.AdditionalProperties[util.AdditionalPropertiesObjectPatches] = `{"Service/virt-api": "[{\"op\":\"replace\",\"path\":\"/spec/type\",\"value\":\"LoadBalancer\"}]"}`
+			lbConfig.AdditionalProperties[util.AdditionalPropertiesLoadBalancerServiceAnnotations] = `{"service.beta.kubernetes.io/aws-load-balancer-internal": "true"}`
+
+			strategy, err := GenerateCurrentInstallStrategy(lbConfig, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			for _, service := range strategy.Services() {
+				if service.Name == "virt-api" {
+					Expect(service.Annotations).To(HaveKeyWithValue("service.beta.kubernetes.io/aws-load-balancer-internal", "true"))
+				} else {
+					Expect(service.Annotations).ToNot(HaveKey("service.beta.kubernetes.io/aws-load-balancer-internal"))
+				}
+			}
+		})
+	})
+
+	Context("config map checksums", func() {
+		findDeployment := func(strategy *Strategy, name string) *appsv1.Deployment {
+			for _, deployment := range strategy.Deployments() {
+				if deployment.Name == name {
+					return deployment
+				}
+			}
+			return nil
+		}
+
+		It("should only annotate Deployments which use a config map of the strategy", func() {
+			strategy, err := GenerateCurrentInstallStrategy(config, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			for _, deployment := range strategy.Deployments() {
+				Expect(deployment.Spec.Template.Annotations).ToNot(HaveKey(ConfigMapChecksumAnnotation), deployment.Name)
+			}
+		})
+
+		It("should change when the data of a used config map changes", func() {
+			volumeConfig := getConfig("fake-registry", "v9.9.9")
+			volumeConfig.AdditionalProperties[util.AdditionalPropertiesObjectPatches] = fmt.Sprintf(`{"Deployment/virt-api": "[{\"op\":\"add\",\"path\":\"/spec/template/spec/volumes/-\",\"value\":{\"name\":\"ca\",\"configMap\":{\"name\":\"%s\"}}}]"}`, components.KubeVirtCASecretName)
+
+			strategy, err := GenerateCurrentInstallStrategy(volumeConfig, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			apiDeployment := findDeployment(strategy, "virt-api")
+			Expect(apiDeployment).ToNot(BeNil())
+			checksum := apiDeployment.Spec.Template.Annotations[ConfigMapChecksumAnnotation]
+			Expect(checksum).ToNot(BeEmpty())
+			Expect(findDeployment(strategy, "virt-controller").Spec.Template.Annotations).ToNot(HaveKey(ConfigMapChecksumAnnotation))
+
+			Expect(strategy.addConfigMapChecksums()).To(Succeed())
+			Expect(apiDeployment.Spec.Template.Annotations).To(HaveKeyWithValue(ConfigMapChecksumAnnotation, checksum))
+
+			for _, configMap := range strategy.ConfigMaps() {
+				if configMap.Name == components.KubeVirtCASecretName {
+					configMap.Data = map[string]string{components.CABundleKey: "new-bundle"}
+				}
+			}
+			Expect(strategy.addConfigMapChecksums()).To(Succeed())
+			Expect(apiDeployment.Spec.Template.Annotations[ConfigMapChecksumAnnotation]).ToNot(Equal(checksum))
+		})
+	})
+
+	Context("release label", func() {
+		It("should label all objects with the version and keep the label when dumped and loaded", func() {
+			strategy, err := GenerateCurrentInstallStrategy(getConfig("fake-registry", "v9.9.9"), "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			loaded, err := loadInstallStrategyFromBytes(string(dumpInstallStrategyToBytes(strategy)))
+			Expect(err).ToNot(HaveOccurred())
+
+			for _, s := range []*Strategy{strategy, loaded} {
+				Expect(s.Deployments()).ToNot(BeEmpty())
+				Expect(s.CRDs()).ToNot(BeEmpty())
+				for _, deployment := range s.Deployments() {
+					Expect(deployment.Labels).To(HaveKeyWithValue(v1.AppVersionLabel, "v9.9.9"), deployment.Name)
+				}
+				for _, crd := range s.CRDs() {
+					Expect(crd.Labels).To(HaveKeyWithValue(v1.AppVersionLabel, "v9.9.9"), crd.Name)
+				}
+			}
+		})
+
+		It("should use the product version if it is set", func() {
+			versionConfig := getConfig("fake-registry", "v9.9.9")
+			versionConfig.AdditionalProperties[util.ProductVersionKey] = "1.2.3"
+
+			strategy, err := GenerateCurrentInstallStrategy(versionConfig, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			for _, o := range strategy.objects() {
+				Expect(o.obj.GetLabels()).To(HaveKeyWithValue(v1.AppVersionLabel, "1.2.3"), "%s/%s", o.kind, o.obj.GetName())
+			}
+		})
+
+		DescribeTable("should fall back to the KubeVirt version if the product version is", func(productVersion string) {
+			versionConfig := getConfig("fake-registry", "v9.9.9")
+			versionConfig.AdditionalProperties[util.ProductVersionKey] = productVersion
+
+			strategy, err := GenerateCurrentInstallStrategy(versionConfig, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			for _, o := range strategy.objects() {
+				Expect(o.obj.GetLabels()).To(HaveKeyWithValue(v1.AppVersionLabel, "v9.9.9"), "%s/%s", o.kind, o.obj.GetName())
+			}
+		},
+			Entry("empty", ""),
+			Entry("not a valid label", "-1.2.3-"),
+		)
+	})
+
+	Context("service session affinity", func() {
+		It("should set ClientIP affinity with the timeout on the requested service only", func() {
+			affinityConfig := getConfig("fake-registry", "v9.9.9")
+			affinityConfig.AdditionalProperties[util.AdditionalPropertiesServiceSessionAffinity] = `{"` + components.PrometheusServiceName + `": 3600}`
+
+			strategy, err := GenerateCurrentInstallStrategy(affinityConfig, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			for _, service := range strategy.Services() {
+				if service.Name == components.PrometheusServiceName {
+					Expect(service.Spec.SessionAffinity).To(Equal(corev1.ServiceAffinityClientIP))
+					Expect(service.Spec.SessionAffinityConfig.ClientIP.TimeoutSeconds).To(HaveValue(BeEquivalentTo(3600)))
+				} else {
+					Expect(service.Spec.SessionAffinityConfig).To(BeNil(), service.Name)
+				}
+			}
+		})
+
+		DescribeTable("should reject", func(value, expected string) {
+			affinityConfig := getConfig("fake-registry", "v9.9.9")
+			affinityConfig.AdditionalProperties[util.AdditionalPropertiesServiceSessionAffinity] = value
+
+			_, err := GenerateCurrentInstallStrategy(affinityConfig, "openshift-monitoring", namespace)
+			Expect(err).To(MatchError(expected))
+		},
+			Entry("an unknown service", `{"unknown": 60}`, "session affinity for unknown service unknown"),
+			Entry("a timeout above one day", `{"virt-api": 86401}`, "invalid session affinity timeout 86401 for service virt-api, it has to be between 1 and 86400 seconds"),
+		)
+	})
+
+	Context("RBAC annotations", func() {
+		It("should be added to the RBAC objects only", func() {
+			rbacConfig := getConfig("fake-registry", "v9.9.9")
+			rbacConfig.AdditionalProperties[util.AdditionalPropertiesRBACAnnotations] = `{"security.example.org/audit": "required"}`
+
+			strategy, err := GenerateCurrentInstallStrategy(rbacConfig, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(strategy.ClusterRoles()).ToNot(BeEmpty())
+			Expect(strategy.RoleBindings()).ToNot(BeEmpty())
+
+			for _, cr := range strategy.ClusterRoles() {
+				Expect(cr.Annotations).To(HaveKeyWithValue("security.example.org/audit", "required"), cr.Name)
+			}
+			for _, rb := range strategy.RoleBindings() {
+				Expect(rb.Annotations).To(HaveKeyWithValue("security.example.org/audit", "required"), rb.Name)
+			}
+			for _, sa := range strategy.ServiceAccounts() {
+				Expect(sa.Annotations).ToNot(HaveKey("security.example.org/audit"), sa.Name)
+			}
+		})
+	})
+
+	Context("virt-handler extra volumes", func() {
+		It("should add the extra volumes and mounts alongside the defaults", func() {
+			defaultStrategy, err := GenerateCurrentInstallStrategy(config, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+			defaultPod := defaultStrategy.DaemonSets()[0].Spec.Template.Spec
+
+			volumeConfig := getConfig("fake-registry", "v9.9.9")
+			volumeConfig.AdditionalProperties[util.AdditionalPropertiesHandlerExtraVolumes] = `[{"name":"csi","hostPath":{"path":"/var/lib/csi"}}]`
+			volumeConfig.AdditionalProperties[util.AdditionalPropertiesHandlerExtraVolumeMounts] = `[{"name":"csi","mountPath":"/var/lib/csi"}]`
+
+			strategy, err := GenerateCurrentInstallStrategy(volumeConfig, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+			pod := strategy.DaemonSets()[0].Spec.Template.Spec
+
+			Expect(pod.Volumes).To(HaveLen(len(defaultPod.Volumes) + 1))
+			Expect(pod.Volumes).To(ContainElements(defaultPod.Volumes))
+			Expect(pod.Volumes).To(ContainElement(corev1.Volume{
+				Name: "csi",
+				VolumeSource: corev1.VolumeSource{
+					HostPath: &corev1.HostPathVolumeSource{Path: "/var/lib/csi"},
+				},
+			}))
+
+			Expect(pod.Containers[0].VolumeMounts).To(ContainElements(defaultPod.Containers[0].VolumeMounts))
+			Expect(pod.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: "csi", MountPath: "/var/lib/csi"}))
+		})
+
+		DescribeTable("should reject extras colliding with the defaults", func(volumes, mounts, expectedErr string) {
+			volumeConfig := getConfig("fake-registry", "v9.9.9")
+			volumeConfig.AdditionalProperties[util.AdditionalPropertiesHandlerExtraVolumes] = volumes
+			volumeConfig.AdditionalProperties[util.AdditionalPropertiesHandlerExtraVolumeMounts] = mounts
+
+			_, err := GenerateCurrentInstallStrategy(volumeConfig, "openshift-monitoring", namespace)
+			Expect(err).To(MatchError(expectedErr))
+		},
+			Entry("by volume name",
+				`[{"name":"libvirt-runtimes","emptyDir":{}}]`, `[]`,
+				"extra volume libvirt-runtimes of virt-handler collides with an existing volume"),
+			Entry("by mount path",
+				`[{"name":"csi","emptyDir":{}}]`, `[{"name":"csi","mountPath":"/var/run/kubevirt-libvirt-runtimes"}]`,
+				"extra volume mount of virt-handler at /var/run/kubevirt-libvirt-runtimes collides with an existing mount"),
+		)
+	})
+
+	Context("extended printer columns", func() {
+		findCRD := func(strategy *Strategy, name string) *extv1.CustomResourceDefinition {
+			for _, crd := range strategy.CRDs() {
+				if crd.Name == name {
+					return crd
+				}
+			}
+			return nil
+		}
+
+		columnNames := func(crd *extv1.CustomResourceDefinition) []string {
+			var names []string
+			for _, column := range crd.Spec.Versions[0].AdditionalPrinterColumns {
+				names = append(names, column.Name)
+			}
+			return names
+		}
+
+		It("should not be added by default", func() {
+			strategy, err := GenerateCurrentInstallStrategy(config, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(columnNames(findCRD(strategy, components.VIRTUALMACHINEINSTANCE))).ToNot(ContainElement("Guest-OS"))
+		})
+
+		It("should be added to the VMI and VM CRDs and survive a round trip", func() {
+			columnsConfig := getConfig("fake-registry", "v9.9.9")
+			columnsConfig.AdditionalProperties[util.AdditionalPropertiesExtendedPrinterColumns] = ""
+
+			strategy, err := GenerateCurrentInstallStrategy(columnsConfig, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			newStrategy, err := loadInstallStrategyFromBytes(string(dumpInstallStrategyToBytes(strategy)))
+			Expect(err).ToNot(HaveOccurred())
+
+			for _, s := range []*Strategy{strategy, newStrategy} {
+				vmi := findCRD(s, components.VIRTUALMACHINEINSTANCE)
+				Expect(columnNames(vmi)).To(ContainElements("Phase", "NodeName", "Guest-OS", "QOS-Class", "Migration-Method"))
+				vm := findCRD(s, components.VIRTUALMACHINE)
+				Expect(columnNames(vm)).To(ContainElements("Status", "Run-Strategy", "Created"))
+			}
+		})
+	})
+
+	Context("CRD names", func() {
+		It("should merge extra short names and categories with the defaults and survive a round trip", func() {
+			namesConfig := getConfig("fake-registry", "v9.9.9")
+			namesConfig.AdditionalProperties[util.AdditionalPropertiesCRDShortNames] = `{"virtualmachineinstances.kubevirt.io":["vmi","kvmi"]}`
+			namesConfig.AdditionalProperties[util.AdditionalPropertiesCRDCategories] = `{"virtualmachineinstances.kubevirt.io":["virt"]}`
+
+			strategy, err := GenerateCurrentInstallStrategy(namesConfig, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			newStrategy, err := loadInstallStrategyFromBytes(string(dumpInstallStrategyToBytes(strategy)))
+			Expect(err).ToNot(HaveOccurred())
+
+			for _, s := range []*Strategy{strategy, newStrategy} {
+				for _, crd := range s.CRDs() {
+					switch crd.Name {
+					case components.VIRTUALMACHINEINSTANCE:
+						Expect(crd.Spec.Names.ShortNames).To(Equal([]string{"vmi", "vmis", "kvmi"}))
+						Expect(crd.Spec.Names.Categories).To(Equal([]string{"all", "virt"}))
+					case components.VIRTUALMACHINE:
+						Expect(crd.Spec.Names.ShortNames).To(Equal([]string{"vm", "vms"}))
+					}
+				}
+			}
+		})
+	})
+
+	Context("graceful shutdown", func() {
+		It("should not set a preStop hook or grace period by default", func() {
+			strategy, err := GenerateCurrentInstallStrategy(config, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			for _, deployment := range strategy.Deployments() {
+				Expect(deployment.Spec.Template.Spec.TerminationGracePeriodSeconds).To(BeNil(), deployment.Name)
+				Expect(deployment.Spec.Template.Spec.Containers[0].Lifecycle).To(BeNil(), deployment.Name)
+			}
+		})
+
+		It("should set the configured preStop hook and grace period on all deployments", func() {
+			shutdownConfig := getConfig("fake-registry", "v9.9.9")
+			shutdownConfig.AdditionalProperties[util.AdditionalPropertiesDeploymentPreStopCommand] = `["/bin/sh","-c","sleep 30"]`
+			shutdownConfig.AdditionalProperties[util.AdditionalPropertiesDeploymentTerminationGracePeriod] = "90"
+
+			strategy, err := GenerateCurrentInstallStrategy(shutdownConfig, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(strategy.ControllerDeployments()).ToNot(BeEmpty())
+
+			for _, deployment := range strategy.Deployments() {
+				pod := deployment.Spec.Template.Spec
+				Expect(pod.TerminationGracePeriodSeconds).To(HaveValue(Equal(int64(90))), deployment.Name)
+				Expect(pod.Containers[0].Lifecycle).ToNot(BeNil(), deployment.Name)
+				Expect(pod.Containers[0].Lifecycle.PreStop.Exec.Command).To(Equal([]string{"/bin/sh", "-c", "sleep 30"}), deployment.Name)
+			}
+		})
+	})
+
+	Context("virt-api metrics TLS", func() {
+		metricsPort := func(deployment *appsv1.Deployment) int32 {
+			for _, port := range deployment.Spec.Template.Spec.Containers[0].Ports {
+				if port.Name == "metrics" {
+					return port.ContainerPort
+				}
+			}
+			return 0
+		}
+
+		hasMetricsCertMount := func(deployment *appsv1.Deployment) bool {
+			for _, mount := range deployment.Spec.Template.Spec.Containers[0].VolumeMounts {
+				if mount.Name == components.VirtApiMetricsCertSecretName {
+					return true
+				}
+			}
+			return false
+		}
+
+		It("should serve metrics on the API port by default", func() {
+			strategy, err := GenerateCurrentInstallStrategy(config, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(strategy.ApiDeployments()).To(HaveLen(1))
+			deployment := strategy.ApiDeployments()[0]
+			Expect(metricsPort(deployment)).To(Equal(int32(8443)))
+			Expect(hasMetricsCertMount(deployment)).To(BeFalse())
+		})
+
+		It("should move metrics to the TLS port and mount the managed certificate when enabled", func() {
+			tlsConfig := getConfig("fake-registry", "v9.9.9")
+			tlsConfig.AdditionalProperties[util.AdditionalPropertiesApiMetricsTLS] = ""
+
+			strategy, err := GenerateCurrentInstallStrategy(tlsConfig, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(strategy.ApiDeployments()).To(HaveLen(1))
+			deployment := strategy.ApiDeployments()[0]
+			Expect(metricsPort(deployment)).To(Equal(int32(components.VirtApiMetricsTLSPort)))
+			Expect(deployment.Spec.Template.Spec.Containers[0].Args).To(ContainElements("--metrics-port", "8444"))
+			Expect(hasMetricsCertMount(deployment)).To(BeTrue())
+
+			for _, service := range strategy.Services() {
+				if service.Name == components.PrometheusServiceName {
+					Expect(service.Spec.Ports[0].TargetPort.StrVal).To(Equal("metrics"))
+				}
+			}
+
+			var secretNames []string
+			for _, secret := range strategy.CertificateSecrets() {
+				secretNames = append(secretNames, secret.Name)
+			}
+			Expect(secretNames).To(ContainElement(components.VirtApiMetricsCertSecretName))
+		})
+	})
+
+	Context("storage class defaults", func() {
+		findConfigMap := func(strategy *Strategy) *corev1.ConfigMap {
+			for _, configMap := range strategy.ConfigMaps() {
+				if configMap.Name == components.StorageClassDefaultsConfigMapName {
+					return configMap
+				}
+			}
+			return nil
+		}
+
+		It("should not generate a config map by default", func() {
+			strategy, err := GenerateCurrentInstallStrategy(config, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(findConfigMap(strategy)).To(BeNil())
+		})
+
+		It("should generate a config map with the provided defaults which survives a round trip", func() {
+			storageConfig := getConfig("fake-registry", "v9.9.9")
+			storageConfig.AdditionalProperties[util.AdditionalPropertiesStorageClassDefaults] = `{"default":"local","ceph-rbd":"block"}`
+
+			strategy, err := GenerateCurrentInstallStrategy(storageConfig, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			configMap := findConfigMap(strategy)
+			Expect(configMap).ToNot(BeNil())
+			Expect(configMap.Namespace).To(Equal(namespace))
+			Expect(configMap.Data).To(Equal(map[string]string{"default": "local", "ceph-rbd": "block"}))
+
+			newStrategy, err := loadInstallStrategyFromBytes(string(dumpInstallStrategyToBytes(strategy)))
+			Expect(err).ToNot(HaveOccurred())
+			loaded := findConfigMap(newStrategy)
+			Expect(loaded).ToNot(BeNil())
+			Expect(loaded.Data).To(Equal(configMap.Data))
+		})
+	})
+
+	Context("config map binary data", func() {
+		It("should generate config maps whose binary data survives a round trip", func() {
+			binaryConfig := getConfig("fake-registry", "v9.9.9")
+			// base64 of 0x00 0x61 0x73 0x6d 0xff, which is not valid UTF-8
+			binaryConfig.AdditionalProperties[util.AdditionalPropertiesConfigMapBinaryData] = `{"kubevirt-policy":{"policy.wasm":"AGFzbf8="}}`
+
+			strategy, err := GenerateCurrentInstallStrategy(binaryConfig, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			findConfigMap := func(strategy *Strategy) *corev1.ConfigMap {
+				for _, configMap := range strategy.ConfigMaps() {
+					if configMap.Name == "kubevirt-policy" {
+						return configMap
+					}
+				}
+				return nil
+			}
+			configMap := findConfigMap(strategy)
+			Expect(configMap).ToNot(BeNil())
+			Expect(configMap.Namespace).To(Equal(namespace))
+			Expect(configMap.Data).To(BeEmpty())
+			Expect(configMap.BinaryData).To(Equal(map[string][]byte{"policy.wasm": {0x00, 0x61, 0x73, 0x6d, 0xff}}))
+
+			loaded, err := loadInstallStrategyFromBytes(string(dumpInstallStrategyToBytes(strategy)))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(findConfigMap(loaded)).ToNot(BeNil())
+			Expect(findConfigMap(loaded).BinaryData).To(Equal(configMap.BinaryData))
+
+			encoded, err := encodeInstallStrategy(strategy)
+			Expect(err).ToNot(HaveOccurred())
+			decoded, err := decodeManifests([]byte(encoded))
+			Expect(err).ToNot(HaveOccurred())
+			loaded, err = loadInstallStrategyFromBytes(decoded)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(findConfigMap(loaded).BinaryData).To(Equal(configMap.BinaryData))
+		})
+	})
+
+	Context("object finalizer", func() {
+		const finalizer = "example.org/teardown"
+
+		It("should not add a finalizer by default", func() {
+			strategy, err := GenerateCurrentInstallStrategy(config, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+			for _, o := range strategy.objects() {
+				Expect(o.obj.GetFinalizers()).ToNot(ContainElement(finalizer), "%s/%s", o.kind, o.obj.GetName())
+			}
+		})
+
+		It("should add the configured finalizer to every object except CRDs", func() {
+			finalizerConfig := getConfig("fake-registry", "v9.9.9")
+			finalizerConfig.AdditionalProperties[util.AdditionalPropertiesObjectFinalizer] = finalizer
+
+			strategy, err := GenerateCurrentInstallStrategy(finalizerConfig, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(strategy.DaemonSets()).ToNot(BeEmpty())
+			Expect(strategy.Deployments()).ToNot(BeEmpty())
+
+			for _, o := range strategy.objects() {
+				if o.kind == "CustomResourceDefinition" {
+					Expect(o.obj.GetFinalizers()).ToNot(ContainElement(finalizer), "%s/%s", o.kind, o.obj.GetName())
+				} else {
+					Expect(o.obj.GetFinalizers()).To(ContainElement(finalizer), "%s/%s", o.kind, o.obj.GetName())
+				}
+			}
+		})
+	})
+
+	Context("service account name overrides", func() {
+		const overriddenName = "irsa-virt-controller"
+
+		It("should propagate the name to the ServiceAccount, the Deployment and its bindings", func() {
+			namesConfig := getConfig("fake-registry", "v9.9.9")
+			namesConfig.AdditionalProperties[util.AdditionalPropertiesServiceAccountNames] = `{"virt-controller": "` + overriddenName + `"}`
+
+			strategy, err := GenerateCurrentInstallStrategy(namesConfig, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			var saNames []string
+			for _, sa := range strategy.ServiceAccounts() {
+				saNames = append(saNames, sa.Name)
+			}
+			Expect(saNames).To(ContainElement(overriddenName))
+			Expect(saNames).ToNot(ContainElement(components.ControllerServiceAccountName))
+			Expect(saNames).To(ContainElement(components.ApiServiceAccountName))
+
+			for _, deployment := range strategy.Deployments() {
+				if deployment.Name == components.VirtControllerName {
+					Expect(deployment.Spec.Template.Spec.ServiceAccountName).To(Equal(overriddenName))
+				} else {
+					Expect(deployment.Spec.Template.Spec.ServiceAccountName).ToNot(Equal(overriddenName))
+				}
+			}
+
+			var subjects []rbacv1.Subject
+			for _, binding := range strategy.ClusterRoleBindings() {
+				subjects = append(subjects, binding.Subjects...)
+			}
+			for _, binding := range strategy.RoleBindings() {
+				subjects = append(subjects, binding.Subjects...)
+			}
+			var subjectNames []string
+			for _, subject := range subjects {
+				if subject.Kind == rbacv1.ServiceAccountKind && subject.Namespace == namespace {
+					subjectNames = append(subjectNames, subject.Name)
+				}
+			}
+			Expect(subjectNames).To(ContainElement(overriddenName))
+			Expect(subjectNames).ToNot(ContainElement(components.ControllerServiceAccountName))
+
+			for _, scc := range strategy.SCCs() {
+				Expect(scc.Users).ToNot(ContainElement("system:serviceaccount:" + namespace + ":" + components.ControllerServiceAccountName))
+			}
+		})
+
+		It("should fail for an unknown component", func() {
+			namesConfig := getConfig("fake-registry", "v9.9.9")
+			namesConfig.AdditionalProperties[util.AdditionalPropertiesServiceAccountNames] = `{"virt-unknown": "name"}`
+
+			_, err := GenerateCurrentInstallStrategy(namesConfig, "openshift-monitoring", namespace)
+			Expect(err).To(MatchError(ContainSubstring("unknown component virt-unknown")))
+		})
+
+		It("should fail for an invalid name", func() {
+			namesConfig := getConfig("fake-registry", "v9.9.9")
+			namesConfig.AdditionalProperties[util.AdditionalPropertiesServiceAccountNames] = `{"virt-controller": "Not_Valid"}`
+
+			_, err := GenerateCurrentInstallStrategy(namesConfig, "openshift-monitoring", namespace)
+			Expect(err).To(MatchError(ContainSubstring("invalid service account name")))
+		})
+	})
+
+	Context("container overrides", func() {
+
+		It("should replace the args of the virt-controller container", func() {
+			overrideConfig := getConfig("fake-registry", "v9.9.9")
+			overrideConfig.AdditionalProperties[util.AdditionalPropertiesContainerOverrides] = `{"virt-controller": {"args": ["--v", "9"]}}`
+
+			strategy, err := GenerateCurrentInstallStrategy(overrideConfig, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			controllers := strategy.ControllerDeployments()
+			Expect(controllers).To(HaveLen(1))
+			container := controllers[0].Spec.Template.Spec.Containers[0]
+			Expect(container.Name).To(Equal(components.VirtControllerName))
+			Expect(container.Args).To(Equal([]string{"--v", "9"}))
+			Expect(container.Command).To(ContainElement("virt-controller"))
+
+			for _, deployment := range strategy.ApiDeployments() {
+				Expect(deployment.Spec.Template.Spec.Containers[0].Args).ToNot(Equal([]string{"--v", "9"}))
+			}
+		})
+
+		It("should fail for an unknown component", func() {
+			overrideConfig := getConfig("fake-registry", "v9.9.9")
+			overrideConfig.AdditionalProperties[util.AdditionalPropertiesContainerOverrides] = `{"virt-unknown": {"command": ["sleep"]}}`
+
+			_, err := GenerateCurrentInstallStrategy(overrideConfig, "openshift-monitoring", namespace)
+			Expect(err).To(MatchError("container override for unknown component virt-unknown"))
+		})
+	})
+
+	Context("with an architecture", func() {
+		It("should use architecture specific images and node affinity", func() {
+			archConfig := getConfig("fake-registry", "v9.9.9")
+			archConfig.AdditionalProperties[util.AdditionalPropertiesArchitecture] = "arm64"
+
+			strategy, err := GenerateCurrentInstallStrategy(archConfig, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			var podSpecs []*corev1.PodSpec
+			for _, deployment := range strategy.Deployments() {
+				podSpecs = append(podSpecs, &deployment.Spec.Template.Spec)
+			}
+			for _, daemonSet := range strategy.DaemonSets() {
+				podSpecs = append(podSpecs, &daemonSet.Spec.Template.Spec)
+			}
+			Expect(podSpecs).ToNot(BeEmpty())
+
+			for _, podSpec := range podSpecs {
+				for _, container := range podSpec.Containers {
+					Expect(container.Image).To(HaveSuffix(":v9.9.9-arm64"))
+				}
+				Expect(podSpec.Affinity).ToNot(BeNil())
+				Expect(podSpec.Affinity.NodeAffinity).ToNot(BeNil())
+				terms := podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+				Expect(terms).To(HaveLen(1))
+				Expect(terms[0].MatchExpressions).To(ContainElement(corev1.NodeSelectorRequirement{
+					Key:      corev1.LabelArchStable,
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   []string{"arm64"},
+				}))
+			}
+		})
+
+		It("should not pin workloads to an architecture by default", func() {
+			strategy, err := GenerateCurrentInstallStrategy(config, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			for _, deployment := range strategy.Deployments() {
+				Expect(deployment.Spec.Template.Spec.Containers[0].Image).To(HaveSuffix(":v9.9.9"))
+				Expect(deployment.Spec.Template.Spec.Affinity.NodeAffinity).To(BeNil())
+			}
+		})
+	})
+
 	Context("should match", func() {
 		It("the most recent install strategy.", func() {
 			var configMaps []*corev1.ConfigMap
@@ -284,6 +1518,52 @@ var _ = Describe("Install Strategy", func() {
 			_, err = LoadInstallStrategyFromCache(stores, config)
 			Expect(err).ToNot(HaveOccurred())
 		})
+		Context("requiring the managed-by label", func() {
+			var stores util.Stores
+
+			BeforeEach(func() {
+				stores = util.Stores{}
+				stores.InstallStrategyConfigMapCache = cache.NewStore(cache.MetaNamespaceKeyFunc)
+			})
+
+			It("should accept a generated install strategy", func() {
+				configMap, err := NewInstallStrategyConfigMap(config, "openshift-monitoring", namespace)
+				Expect(err).ToNot(HaveOccurred())
+				configMap.Name = "kubevirt-install-strategy-abcde"
+				Expect(stores.InstallStrategyConfigMapCache.Add(configMap)).To(Succeed())
+
+				strategy, err := LoadInstallStrategyFromCache(stores, config, RequireManagedByLabel())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(strategy.Deployments()).ToNot(BeEmpty())
+
+				_, err = loadInstallStrategyFromConfigMaps([]*corev1.ConfigMap{configMap}, RequireManagedByLabel())
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should fail naming the config map without the label", func() {
+				strategy, err := GenerateCurrentInstallStrategy(config, "openshift-monitoring", namespace)
+				Expect(err).ToNot(HaveOccurred())
+				configMap := &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "unmanaged",
+						Namespace: config.GetNamespace(),
+						Annotations: map[string]string{
+							v1.InstallStrategyIdentifierAnnotation: config.GetDeploymentID(),
+						},
+					},
+					Data: map[string]string{
+						"manifests": string(dumpInstallStrategyToBytes(strategy)),
+					},
+				}
+				Expect(stores.InstallStrategyConfigMapCache.Add(configMap)).To(Succeed())
+
+				_, err = LoadInstallStrategyFromCache(stores, config, RequireManagedByLabel())
+				Expect(err).To(MatchError(ContainSubstring("%s/unmanaged", config.GetNamespace())))
+
+				_, err = LoadInstallStrategyFromCache(stores, config)
+				Expect(err).ToNot(HaveOccurred())
+			})
+		})
 		It("a gzip+base64 encoded install strategy.", func() {
 			stores := util.Stores{}
 			stores.InstallStrategyConfigMapCache = cache.NewStore(cache.MetaNamespaceKeyFunc)
@@ -294,6 +1574,132 @@ var _ = Describe("Install Strategy", func() {
 			Expect(err).ToNot(HaveOccurred())
 		})
 	})
+
+	Context("install strategy config map", func() {
+		It("should be mutable by default", func() {
+			configMap, err := NewInstallStrategyConfigMap(config, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(configMap.Immutable).To(BeNil())
+		})
+
+		It("should be immutable when requested and still load", func() {
+			immutableConfig := getConfig("fake-registry", "v9.9.9")
+			immutableConfig.AdditionalProperties[util.AdditionalPropertiesImmutableInstallStrategy] = ""
+
+			configMap, err := NewInstallStrategyConfigMap(immutableConfig, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(configMap.Immutable).To(HaveValue(BeTrue()))
+
+			stores := util.Stores{}
+			stores.InstallStrategyConfigMapCache = cache.NewStore(cache.MetaNamespaceKeyFunc)
+			Expect(stores.InstallStrategyConfigMapCache.Add(configMap)).To(Succeed())
+			_, err = LoadInstallStrategyFromCache(stores, immutableConfig)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should recreate an existing immutable config map instead of updating it", func() {
+			immutable := true
+			existing := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "kubevirt-install-strategy-abcde", Namespace: namespace},
+				Immutable:  &immutable,
+				Data:       map[string]string{"manifests": "old"},
+			}
+			client := fake.NewSimpleClientset(existing)
+
+			configMap := existing.DeepCopy()
+			configMap.Data = map[string]string{"manifests": "new"}
+			Expect(storeInstallStrategyConfigMap(client.CoreV1(), configMap)).To(Succeed())
+
+			var verbs []string
+			for _, action := range client.Actions() {
+				verbs = append(verbs, action.GetVerb())
+			}
+			Expect(verbs).To(Equal([]string{"create", "get", "delete", "create"}))
+			stored, err := client.CoreV1().ConfigMaps(namespace).Get(context.Background(), configMap.Name, metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stored.Data).To(HaveKeyWithValue("manifests", "new"))
+		})
+
+		It("should update an existing mutable config map", func() {
+			existing := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "kubevirt-install-strategy-abcde", Namespace: namespace},
+				Data:       map[string]string{"manifests": "old"},
+			}
+			client := fake.NewSimpleClientset(existing)
+
+			configMap := existing.DeepCopy()
+			configMap.Data = map[string]string{"manifests": "new"}
+			Expect(storeInstallStrategyConfigMap(client.CoreV1(), configMap)).To(Succeed())
+
+			var verbs []string
+			for _, action := range client.Actions() {
+				verbs = append(verbs, action.GetVerb())
+			}
+			Expect(verbs).To(Equal([]string{"create", "get", "update"}))
+		})
+
+		It("should merge a strategy split across config maps", func() {
+			strategy, err := GenerateCurrentInstallStrategy(config, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			objects := strategy.objects()
+			first, second := &Strategy{}, &Strategy{}
+			for i, o := range objects {
+				if i < len(objects)/2 {
+					first.addObject(o.obj)
+				} else {
+					second.addObject(o.obj)
+				}
+			}
+			newConfigMap := func(name string, part *Strategy) *corev1.ConfigMap {
+				manifests, err := encodeInstallStrategy(part)
+				Expect(err).ToNot(HaveOccurred())
+				return &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        name,
+						Annotations: map[string]string{v1.InstallStrategyConfigMapEncoding: ManifestsEncodingGzipBase64},
+					},
+					Data: map[string]string{"manifests": manifests},
+				}
+			}
+
+			merged, err := loadInstallStrategyFromConfigMaps([]*corev1.ConfigMap{newConfigMap("first", first), newConfigMap("second", second)})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(merged.objects()).To(HaveLen(len(objects)))
+			loaded, err := loadInstallStrategyFromBytes(string(dumpInstallStrategyToBytes(strategy)))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(StrategyEqual(loaded, merged)).To(BeTrue())
+		})
+
+		It("should reject objects contained in several config maps", func() {
+			configMap, err := NewInstallStrategyConfigMap(config, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+			configMap.Name = "first"
+			other := configMap.DeepCopy()
+			other.Name = "second"
+
+			_, err = loadInstallStrategyFromConfigMaps([]*corev1.ConfigMap{configMap, other})
+			Expect(err).To(MatchError(And(
+				ContainSubstring("install strategy config maps contain duplicate objects"),
+				ContainSubstring(fmt.Sprintf("Deployment %s/virt-api in first and second", namespace)),
+			)))
+		})
+
+		It("should fail if the encoded strategy exceeds the ConfigMap size limit", func() {
+			strategy, err := GenerateCurrentInstallStrategy(config, "openshift-monitoring", namespace)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = encodeInstallStrategy(strategy)
+			Expect(err).ToNot(HaveOccurred())
+
+			// random data hardly compresses, so the strategy stays too large after encoding
+			strategy.configMaps = append(strategy.configMaps, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "large"},
+				Data:       map[string]string{"data": rand.String(3 * 1024 * 1024)},
+			})
+			_, err = encodeInstallStrategy(strategy)
+			Expect(err).To(MatchError(ContainSubstring("exceeds the ConfigMap size limit of 1048576 bytes")))
+		})
+	})
 })
 
 func newSA(namespace string, name string) *corev1.ServiceAccount {
@@ -0,0 +1,693 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package install
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/discovery"
+
+	v1 "kubevirt.io/api/core/v1"
+
+	"kubevirt.io/kubevirt/pkg/virt-operator/resource/generate/components"
+)
+
+// AssertConsistentImageTag verifies that every container image of the Deployments and DaemonSets in the strategy
+// carries the expected tag or digest. All mismatches are reported.
+func AssertConsistentImageTag(strategy *Strategy, expectedTag string) error {
+	var errs []error
+	check := func(kind, namespace, name string, podSpec *corev1.PodSpec) {
+		containers := append(append([]corev1.Container{}, podSpec.InitContainers...), podSpec.Containers...)
+		for _, container := range containers {
+			if tag := imageTag(NormalizeImageReference(container.Image)); tag != expectedTag {
+				errs = append(errs, fmt.Errorf("%s %s/%s container %s uses image %s, expected tag %s", kind, namespace, name, container.Name, container.Image, expectedTag))
+			}
+		}
+	}
+
+	for _, deployment := range strategy.deployments {
+		check("Deployment", deployment.Namespace, deployment.Name, &deployment.Spec.Template.Spec)
+	}
+	for _, daemonSet := range strategy.daemonSets {
+		check("DaemonSet", daemonSet.Namespace, daemonSet.Name, &daemonSet.Spec.Template.Spec)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// SelectorlessServiceAnnotation marks a Service which is deliberately generated without a selector, e.g. a headless
+// Service with manually managed endpoints
+const SelectorlessServiceAnnotation = "kubevirt.io/selectorless"
+
+// ValidateServiceSelectors verifies that every Service of the strategy has a selector without empty keys. ExternalName
+// Services and Services marked with SelectorlessServiceAnnotation are exempt.
+func ValidateServiceSelectors(strategy *Strategy) error {
+	var errs []error
+	for _, service := range strategy.services {
+		if service.Spec.Type == corev1.ServiceTypeExternalName {
+			continue
+		}
+		if _, selectorless := service.Annotations[SelectorlessServiceAnnotation]; selectorless {
+			continue
+		}
+
+		if len(service.Spec.Selector) == 0 {
+			errs = append(errs, fmt.Errorf("Service %s/%s has an empty selector", service.Namespace, service.Name))
+			continue
+		}
+		if _, ok := service.Spec.Selector[""]; ok {
+			errs = append(errs, fmt.Errorf("Service %s/%s has a selector with an empty key", service.Namespace, service.Name))
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// ValidateWorkloadSelectors verifies that every Deployment and DaemonSet of the strategy has a non-empty selector whose
+// matchLabels and matchExpressions select its pod template labels, otherwise the API server rejects the workload.
+func ValidateWorkloadSelectors(strategy *Strategy) error {
+	var errs []error
+	check := func(kind, namespace, name string, selector *metav1.LabelSelector, template *corev1.PodTemplateSpec) {
+		if selector == nil || (len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0) {
+			errs = append(errs, fmt.Errorf("%s %s/%s has an empty selector", kind, namespace, name))
+			return
+		}
+		labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s %s/%s has an invalid selector: %v", kind, namespace, name, err))
+			return
+		}
+		if !labelSelector.Matches(labels.Set(template.Labels)) {
+			errs = append(errs, fmt.Errorf("%s %s/%s selector %s does not match its pod template labels %v", kind, namespace, name, metav1.FormatLabelSelector(selector), template.Labels))
+		}
+	}
+
+	for _, deployment := range strategy.deployments {
+		check("Deployment", deployment.Namespace, deployment.Name, deployment.Spec.Selector, &deployment.Spec.Template)
+	}
+	for _, daemonSet := range strategy.daemonSets {
+		check("DaemonSet", daemonSet.Namespace, daemonSet.Name, daemonSet.Spec.Selector, &daemonSet.Spec.Template)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// ValidateContainerPorts verifies that no two containers of a Deployment or DaemonSet pod template of the strategy
+// declare the same container port and protocol. All conflicts are reported.
+func ValidateContainerPorts(strategy *Strategy) error {
+	var errs []error
+	check := func(kind, namespace, name string, podSpec *corev1.PodSpec) {
+		declaredBy := map[string]string{}
+		for _, container := range podSpec.Containers {
+			for _, port := range container.Ports {
+				protocol := port.Protocol
+				if protocol == "" {
+					protocol = corev1.ProtocolTCP
+				}
+				key := fmt.Sprintf("%d/%s", port.ContainerPort, protocol)
+				if other, exists := declaredBy[key]; exists && other != container.Name {
+					errs = append(errs, fmt.Errorf("%s %s/%s containers %s and %s both declare port %s", kind, namespace, name, other, container.Name, key))
+					continue
+				}
+				declaredBy[key] = container.Name
+			}
+		}
+	}
+
+	for _, deployment := range strategy.deployments {
+		check("Deployment", deployment.Namespace, deployment.Name, &deployment.Spec.Template.Spec)
+	}
+	for _, daemonSet := range strategy.daemonSets {
+		check("DaemonSet", daemonSet.Namespace, daemonSet.Name, &daemonSet.Spec.Template.Spec)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// ValidatePodDisruptionBudgets verifies that every Deployment of the strategy with more than one replica is covered by
+// a PodDisruptionBudget. The Reconciler creates the budgets for the API, controller and export proxy Deployments, any
+// other multi-replica Deployment is reported.
+func ValidatePodDisruptionBudgets(strategy *Strategy) error {
+	covered := map[*appsv1.Deployment]bool{}
+	for _, deployments := range [][]*appsv1.Deployment{strategy.ApiDeployments(), strategy.ControllerDeployments(), strategy.ExportProxyDeployments()} {
+		for _, deployment := range deployments {
+			covered[deployment] = true
+		}
+	}
+
+	var errs []error
+	for _, deployment := range strategy.deployments {
+		if deployment.Spec.Replicas == nil || *deployment.Spec.Replicas <= 1 || covered[deployment] {
+			continue
+		}
+		errs = append(errs, fmt.Errorf("Deployment %s/%s has %d replicas but no PodDisruptionBudget", deployment.Namespace, deployment.Name, *deployment.Spec.Replicas))
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// ValidateServiceTargetPorts verifies that every named target port of a Service of the strategy is declared by a
+// container of each Deployment and DaemonSet the Service selects. Endpoints of pods without the port stay unreachable.
+func ValidateServiceTargetPorts(strategy *Strategy) error {
+	type workload struct {
+		kind, namespace, name string
+		podTemplate           *corev1.PodTemplateSpec
+	}
+	var workloads []workload
+	for _, deployment := range strategy.deployments {
+		workloads = append(workloads, workload{"Deployment", deployment.Namespace, deployment.Name, &deployment.Spec.Template})
+	}
+	for _, daemonSet := range strategy.daemonSets {
+		workloads = append(workloads, workload{"DaemonSet", daemonSet.Namespace, daemonSet.Name, &daemonSet.Spec.Template})
+	}
+
+	var errs []error
+	for _, service := range strategy.services {
+		if len(service.Spec.Selector) == 0 {
+			continue
+		}
+		for _, w := range workloads {
+			if w.namespace != service.Namespace || !selectorSubset(service.Spec.Selector, w.podTemplate.Labels) {
+				continue
+			}
+
+			declared := map[string]bool{}
+			for _, container := range w.podTemplate.Spec.Containers {
+				for _, port := range container.Ports {
+					if port.Name != "" {
+						declared[port.Name] = true
+					}
+				}
+			}
+			for _, port := range service.Spec.Ports {
+				if port.TargetPort.Type == intstr.String && !declared[port.TargetPort.StrVal] {
+					errs = append(errs, fmt.Errorf("Service %s/%s port %d targets port %q which no container of %s %s/%s declares", service.Namespace, service.Name, port.Port, port.TargetPort.StrVal, w.kind, w.namespace, w.name))
+				}
+			}
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// ValidateServiceOverlap verifies that no two Services of the strategy in the same namespace expose the same port and
+// protocol for the same pods. Services overlap if the selector of one is a subset of the selector of the other.
+func ValidateServiceOverlap(strategy *Strategy) error {
+	var errs []error
+	for i, a := range strategy.services {
+		for _, b := range strategy.services[i+1:] {
+			if a.Namespace != b.Namespace || len(a.Spec.Selector) == 0 || len(b.Spec.Selector) == 0 {
+				continue
+			}
+			if !selectorSubset(a.Spec.Selector, b.Spec.Selector) && !selectorSubset(b.Spec.Selector, a.Spec.Selector) {
+				continue
+			}
+			for _, port := range sharedServicePorts(a, b) {
+				errs = append(errs, fmt.Errorf("Services %s/%s and %s/%s select the same pods on port %s", a.Namespace, a.Name, b.Namespace, b.Name, port))
+			}
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// selectorSubset returns true if every key of the subset selects the same value in the selector
+func selectorSubset(subset, selector map[string]string) bool {
+	for key, value := range subset {
+		if v, exists := selector[key]; !exists || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+// sharedServicePorts returns the ports both services expose with the same protocol, formatted as port/protocol
+func sharedServicePorts(a, b *corev1.Service) []string {
+	protocol := func(port corev1.ServicePort) corev1.Protocol {
+		if port.Protocol == "" {
+			return corev1.ProtocolTCP
+		}
+		return port.Protocol
+	}
+
+	var shared []string
+	for _, portA := range a.Spec.Ports {
+		for _, portB := range b.Spec.Ports {
+			if portA.Port == portB.Port && protocol(portA) == protocol(portB) {
+				shared = append(shared, fmt.Sprintf("%d/%s", portA.Port, protocol(portA)))
+			}
+		}
+	}
+	return shared
+}
+
+// ValidateRoleBindingSubjects verifies that every ServiceAccount bound by a RoleBinding of the strategy lives in the
+// operator namespace, in a namespace created by the strategy or in one of the given external namespaces, which are
+// known to exist outside of the strategy, like the monitoring namespace.
+func ValidateRoleBindingSubjects(strategy *Strategy, operatorNamespace string, externalNamespaces ...string) error {
+	known := map[string]bool{operatorNamespace: true}
+	for _, namespace := range strategy.namespaces {
+		known[namespace.Name] = true
+	}
+	for _, namespace := range externalNamespaces {
+		known[namespace] = true
+	}
+
+	var errs []error
+	for _, rb := range strategy.roleBindings {
+		for _, subject := range rb.Subjects {
+			if subject.Kind != rbacv1.ServiceAccountKind {
+				continue
+			}
+			// an empty subject namespace refers to the namespace of the binding
+			namespace := subject.Namespace
+			if namespace == "" {
+				namespace = rb.Namespace
+			}
+			if !known[namespace] {
+				errs = append(errs, fmt.Errorf("RoleBinding %s/%s binds ServiceAccount %s/%s in a namespace which is not created by the install strategy", rb.Namespace, rb.Name, namespace, subject.Name))
+			}
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// allowedWildcardRBAC lists the API groups in which a role of the strategy, keyed by kind and name, may grant all verbs
+// on all resources. The controller manages every resource of the KubeVirt and CDI groups.
+var allowedWildcardRBAC = map[string][]string{
+	"ClusterRole/" + components.ControllerServiceAccountName: {
+		"kubevirt.io",
+		"snapshot.kubevirt.io",
+		"export.kubevirt.io",
+		"cdi.kubevirt.io",
+	},
+}
+
+// AssertNoWildcardRBAC verifies that no ClusterRole or Role of the strategy grants "*" on API groups, resources or
+// verbs, unless the wildcards are limited to an API group allowed for the role.
+func AssertNoWildcardRBAC(strategy *Strategy) error {
+	var errs []error
+	check := func(kind string, meta metav1.ObjectMeta, rules []rbacv1.PolicyRule) {
+		name := meta.Name
+		if meta.Namespace != "" {
+			name = meta.Namespace + "/" + name
+		}
+		allowedGroups := map[string]bool{}
+		for _, group := range allowedWildcardRBAC[kind+"/"+meta.Name] {
+			allowedGroups[group] = true
+		}
+
+		for _, rule := range rules {
+			var wildcards []string
+			if containsWildcard(rule.APIGroups) {
+				wildcards = append(wildcards, "apiGroups")
+			}
+			if containsWildcard(rule.Resources) {
+				wildcards = append(wildcards, "resources")
+			}
+			if containsWildcard(rule.Verbs) {
+				wildcards = append(wildcards, "verbs")
+			}
+			if len(wildcards) == 0 {
+				continue
+			}
+
+			allowed := len(rule.APIGroups) > 0
+			for _, group := range rule.APIGroups {
+				allowed = allowed && allowedGroups[group]
+			}
+			if !allowed {
+				errs = append(errs, fmt.Errorf("%s %s grants \"*\" %s for API groups %v", kind, name, strings.Join(wildcards, ", "), rule.APIGroups))
+			}
+		}
+	}
+
+	for _, clusterRole := range strategy.clusterRoles {
+		check("ClusterRole", clusterRole.ObjectMeta, clusterRole.Rules)
+	}
+	for _, role := range strategy.roles {
+		check("Role", role.ObjectMeta, role.Rules)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+func containsWildcard(values []string) bool {
+	for _, value := range values {
+		if value == rbacv1.ResourceAll {
+			return true
+		}
+	}
+	return false
+}
+
+// clusterScopedKinds are the kinds of the strategy which are not namespaced
+var clusterScopedKinds = map[string]bool{
+	"Namespace":                      true,
+	"ClusterRole":                    true,
+	"ClusterRoleBinding":             true,
+	"CustomResourceDefinition":       true,
+	"ValidatingWebhookConfiguration": true,
+	"MutatingWebhookConfiguration":   true,
+	"APIService":                     true,
+	"SecurityContextConstraints":     true,
+}
+
+// ValidateObjectNamespaces verifies that cluster-scoped objects of the strategy have no namespace and that namespaced
+// objects have the operator namespace or one of the given external namespaces, like the monitoring namespace. A
+// namespaced object without a namespace would end up in the namespace of the client applying it.
+func ValidateObjectNamespaces(strategy *Strategy, operatorNamespace string, externalNamespaces ...string) error {
+	known := map[string]bool{operatorNamespace: true}
+	for _, namespace := range externalNamespaces {
+		known[namespace] = true
+	}
+
+	var errs []error
+	for _, o := range strategy.objects() {
+		namespace, name := o.obj.GetNamespace(), o.obj.GetName()
+		switch {
+		case clusterScopedKinds[o.kind]:
+			if namespace != "" {
+				errs = append(errs, fmt.Errorf("cluster-scoped %s %s has namespace %s", o.kind, name, namespace))
+			}
+		case namespace == "":
+			errs = append(errs, fmt.Errorf("%s %s has no namespace, expected %s", o.kind, name, operatorNamespace))
+		case !known[namespace]:
+			errs = append(errs, fmt.Errorf("%s %s/%s is not in the operator namespace %s", o.kind, namespace, name, operatorNamespace))
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// ValidateConfigMapKeys verifies that the data and binaryData keys of every ConfigMap of the strategy are valid
+// ConfigMap keys and that no key is used in both.
+func ValidateConfigMapKeys(strategy *Strategy) error {
+	var errs []error
+	for _, configMap := range strategy.configMaps {
+		var keys []string
+		for key := range configMap.Data {
+			keys = append(keys, key)
+		}
+		for key := range configMap.BinaryData {
+			if _, exists := configMap.Data[key]; exists {
+				errs = append(errs, fmt.Errorf("ConfigMap %s/%s key %q is used in data and binaryData", configMap.Namespace, configMap.Name, key))
+				continue
+			}
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if msgs := validation.IsConfigMapKey(key); len(msgs) > 0 {
+				errs = append(errs, fmt.Errorf("ConfigMap %s/%s has an invalid key %q: %s", configMap.Namespace, configMap.Name, key, strings.Join(msgs, ", ")))
+			}
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// ValidateAnnotationKeys verifies that the annotation keys of every object of the strategy, and of the pod templates of
+// its Deployments and DaemonSets, are qualified names, as required by the API server
+func ValidateAnnotationKeys(strategy *Strategy) error {
+	var errs []error
+	check := func(kind, name string, annotations map[string]string) {
+		for _, key := range sortedKeys(annotations) {
+			if msgs := validation.IsQualifiedName(key); len(msgs) > 0 {
+				errs = append(errs, fmt.Errorf("%s %s has an invalid annotation key %q: %s", kind, name, key, strings.Join(msgs, ", ")))
+			}
+		}
+	}
+
+	for _, o := range strategy.objects() {
+		name := o.obj.GetName()
+		if o.obj.GetNamespace() != "" {
+			name = o.obj.GetNamespace() + "/" + name
+		}
+		check(o.kind, name, o.obj.GetAnnotations())
+
+		switch obj := o.obj.(type) {
+		case *appsv1.Deployment:
+			check(o.kind, name+" pod template", obj.Spec.Template.Annotations)
+		case *appsv1.DaemonSet:
+			check(o.kind, name+" pod template", obj.Spec.Template.Annotations)
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// ValidateMetricsServiceLabels verifies that the metrics service of the strategy carries the labels the ServiceMonitor
+// scrapes it by. Every missing or differing label is reported.
+func ValidateMetricsServiceLabels(strategy *Strategy) error {
+	service := metricsService(strategy)
+	if service == nil {
+		return fmt.Errorf("metrics service %s is missing", components.PrometheusServiceName)
+	}
+
+	var errs []error
+	for _, key := range sortedKeys(components.PrometheusScrapeLabels()) {
+		expected := components.PrometheusScrapeLabels()[key]
+		if value, ok := service.Labels[key]; !ok {
+			errs = append(errs, fmt.Errorf("metrics service %s/%s is missing label %s=%s", service.Namespace, service.Name, key, expected))
+		} else if value != expected {
+			errs = append(errs, fmt.Errorf("metrics service %s/%s has label %s=%s, expected %s", service.Namespace, service.Name, key, value, expected))
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// RepairMetricsServiceLabels sets the labels the ServiceMonitor scrapes the metrics service by and reports whether any
+// label had to be repaired
+func RepairMetricsServiceLabels(strategy *Strategy) bool {
+	service := metricsService(strategy)
+	if service == nil {
+		return false
+	}
+
+	repaired := false
+	for key, expected := range components.PrometheusScrapeLabels() {
+		if value, ok := service.Labels[key]; ok && value == expected {
+			continue
+		}
+		if service.Labels == nil {
+			service.Labels = map[string]string{}
+		}
+		service.Labels[key] = expected
+		repaired = true
+	}
+	return repaired
+}
+
+func metricsService(strategy *Strategy) *corev1.Service {
+	for _, service := range strategy.services {
+		if service.Name == components.PrometheusServiceName {
+			return service
+		}
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ValidateCRDNames verifies that the name of every CRD of the strategy is its plural name followed by its group, as
+// required by the API server.
+func ValidateCRDNames(strategy *Strategy) error {
+	var errs []error
+	for _, crd := range strategy.crds {
+		if expected := crd.Spec.Names.Plural + "." + crd.Spec.Group; crd.Name != expected {
+			errs = append(errs, fmt.Errorf("CustomResourceDefinition %s does not match its plural name %q and group %q, expected %s", crd.Name, crd.Spec.Names.Plural, crd.Spec.Group, expected))
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// statusSubresourceCRDs are the CRDs whose controllers write the status through the status subresource. The
+// VirtualMachineInstance CRD is left out on purpose, virt-controller and virt-handler update its status together with
+// the rest of the object.
+var statusSubresourceCRDs = []string{
+	components.VIRTUALMACHINE,
+	components.VIRTUALMACHINEINSTANCEREPLICASET,
+	components.VIRTUALMACHINEINSTANCEMIGRATION,
+	components.KUBEVIRT,
+	components.VIRTUALMACHINEPOOL,
+	components.MIGRATIONPOLICY,
+	components.VIRTUALMACHINECLONE,
+}
+
+// ValidateStatusSubresources verifies that every version of the CRDs whose status is written through the status
+// subresource declares it. Without it the API server silently drops the status updates of their controllers.
+func ValidateStatusSubresources(strategy *Strategy) error {
+	expected := map[string]bool{}
+	for _, name := range statusSubresourceCRDs {
+		expected[name] = true
+	}
+
+	var errs []error
+	for _, crd := range strategy.crds {
+		if !expected[crd.Name] {
+			continue
+		}
+		for _, version := range crd.Spec.Versions {
+			if version.Subresources == nil || version.Subresources.Status == nil {
+				errs = append(errs, fmt.Errorf("CustomResourceDefinition %s version %s does not declare the status subresource", crd.Name, version.Name))
+			}
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// AssertDeploymentIdentifier verifies that every object of the strategy either carries the expected install strategy
+// identifier or none at all, as freshly generated objects do. Objects carrying the identifier of another operator
+// deployment are reported.
+func AssertDeploymentIdentifier(strategy *Strategy, expectedID string) error {
+	var errs []error
+	for _, o := range strategy.objects() {
+		id, ok := o.obj.GetAnnotations()[v1.InstallStrategyIdentifierAnnotation]
+		if !ok || id == expectedID {
+			continue
+		}
+		name := o.obj.GetName()
+		if o.obj.GetNamespace() != "" {
+			name = o.obj.GetNamespace() + "/" + name
+		}
+		errs = append(errs, fmt.Errorf("%s %s carries install strategy identifier %q, expected %q", o.kind, name, id, expectedID))
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+const (
+	defaultImageDomain = "docker.io"
+	defaultImageTag    = "latest"
+)
+
+// NormalizeImageReference returns the fully qualified form of an image reference, so that equivalent references
+// compare equal. Images without a registry are resolved against docker.io, official images get the library/ prefix,
+// images without a tag get the latest tag and the tag of an image with a digest is dropped in favor of the digest.
+func NormalizeImageReference(ref string) string {
+	name, digest := ref, ""
+	if i := strings.LastIndex(ref, "@"); i >= 0 {
+		name, digest = ref[:i], ref[i:]
+	}
+
+	tag := ""
+	// a colon before the last slash separates the registry port
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, tag = name[:i], name[i:]
+	}
+
+	domain, path := defaultImageDomain, name
+	if i := strings.Index(name, "/"); i >= 0 {
+		first := name[:i]
+		if strings.ContainsAny(first, ".:") || first == "localhost" {
+			domain, path = first, name[i+1:]
+		}
+	}
+	if domain == "index.docker.io" {
+		domain = defaultImageDomain
+	}
+	if domain == defaultImageDomain && !strings.Contains(path, "/") {
+		path = "library/" + path
+	}
+
+	switch {
+	case digest != "":
+		return domain + "/" + path + digest
+	case tag != "":
+		return domain + "/" + path + tag
+	default:
+		return domain + "/" + path + ":" + defaultImageTag
+	}
+}
+
+// imageTag returns the digest or the tag of an image reference, or an empty string if it has neither
+func imageTag(image string) string {
+	if i := strings.LastIndex(image, "@"); i >= 0 {
+		return image[i+1:]
+	}
+	// a colon before the last slash separates the registry port
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[i+1:]
+	}
+	return ""
+}
+
+// ValidateAgainstDiscovery verifies that the cluster serves the group, version and kind of every object in the
+// strategy. Every kind which the cluster can not serve is reported once.
+func ValidateAgainstDiscovery(strategy *Strategy, discoveryClient discovery.DiscoveryInterface) error {
+	servedKinds := map[schema.GroupVersion]map[string]bool{}
+	reported := map[string]bool{}
+
+	var errs []error
+	for _, o := range strategy.objects() {
+		resource, ok := strategyKindResources[o.kind]
+		if !ok {
+			return fmt.Errorf("no resource known for kind %s", o.kind)
+		}
+		groupVersion := resource.GroupVersion()
+
+		kinds, ok := servedKinds[groupVersion]
+		if !ok {
+			resources, err := discoveryClient.ServerResourcesForGroupVersion(groupVersion.String())
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+			kinds = map[string]bool{}
+			if resources != nil {
+				for _, apiResource := range resources.APIResources {
+					kinds[apiResource.Kind] = true
+				}
+			}
+			servedKinds[groupVersion] = kinds
+		}
+
+		if !kinds[o.kind] && !reported[o.kind] {
+			reported[o.kind] = true
+			errs = append(errs, fmt.Errorf("kind %s of %s is not served by the cluster", o.kind, groupVersion))
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
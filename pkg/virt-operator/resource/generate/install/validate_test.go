@@ -0,0 +1,617 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package install
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/testing"
+
+	v1 "kubevirt.io/api/core/v1"
+
+	"kubevirt.io/kubevirt/pkg/virt-operator/resource/generate/components"
+	"kubevirt.io/kubevirt/pkg/virt-operator/util"
+)
+
+var _ = Describe("Install Strategy validation", func() {
+
+	namespace := "fake-namespace"
+
+	newStrategy := func(registry, version string) *Strategy {
+		config := util.GetTargetConfigFromKV(&v1.KubeVirt{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+			},
+			Spec: v1.KubeVirtSpec{
+				ImageRegistry: registry,
+				ImageTag:      version,
+			},
+		})
+		strategy, err := GenerateCurrentInstallStrategy(config, "openshift-monitoring", namespace)
+		Expect(err).ToNot(HaveOccurred())
+		return strategy
+	}
+
+	Context("image tags", func() {
+
+		DescribeTable("should extract the tag", func(image, expected string) {
+			Expect(imageTag(image)).To(Equal(expected))
+		},
+			Entry("from a tagged image", "quay.io/kubevirt/virt-api:v1.0.0", "v1.0.0"),
+			Entry("from a digest", "quay.io/kubevirt/virt-api@sha256:abcd", "sha256:abcd"),
+			Entry("from a registry with a port", "registry:5000/kubevirt/virt-api:v1.0.0", "v1.0.0"),
+			Entry("not from a registry port without a tag", "registry:5000/kubevirt/virt-api", ""),
+		)
+
+		DescribeTable("should normalize the image reference", func(image, expected string) {
+			Expect(NormalizeImageReference(image)).To(Equal(expected))
+		},
+			Entry("with an implicit registry and tag", "kubevirt/virt-api", "docker.io/kubevirt/virt-api:latest"),
+			Entry("with an implicit registry", "kubevirt/virt-api:v1.0.0", "docker.io/kubevirt/virt-api:v1.0.0"),
+			Entry("of an official image", "busybox", "docker.io/library/busybox:latest"),
+			Entry("of the legacy docker hub registry", "index.docker.io/kubevirt/virt-api:v1.0.0", "docker.io/kubevirt/virt-api:v1.0.0"),
+			Entry("of a fully qualified reference", "docker.io/kubevirt/virt-api:latest", "docker.io/kubevirt/virt-api:latest"),
+			Entry("with a registry port and an implicit tag", "registry:5000/kubevirt/virt-api", "registry:5000/kubevirt/virt-api:latest"),
+			Entry("of localhost", "localhost/virt-api", "localhost/virt-api:latest"),
+			Entry("with a digest", "quay.io/kubevirt/virt-api@sha256:abcd", "quay.io/kubevirt/virt-api@sha256:abcd"),
+			Entry("with a tag and a digest", "quay.io/kubevirt/virt-api:v1.0.0@sha256:abcd", "quay.io/kubevirt/virt-api@sha256:abcd"),
+		)
+
+		It("should treat an image without a tag as latest", func() {
+			strategy := newStrategy("fake-registry", "latest")
+			strategy.deployments[0].Spec.Template.Spec.Containers[0].Image = "fake-registry/virt-api"
+
+			Expect(AssertConsistentImageTag(strategy, "latest")).To(Succeed())
+		})
+
+		It("should accept a consistently tagged strategy", func() {
+			Expect(AssertConsistentImageTag(newStrategy("fake-registry", "v9.9.9"), "v9.9.9")).To(Succeed())
+		})
+
+		It("should accept a consistent digest", func() {
+			Expect(AssertConsistentImageTag(newStrategy("fake-registry", "sha256:abcd"), "sha256:abcd")).To(Succeed())
+		})
+
+		It("should report a container left at an old tag", func() {
+			strategy := newStrategy("fake-registry", "v9.9.9")
+			strategy.deployments[0].Spec.Template.Spec.Containers[0].Image = "fake-registry/virt-api:v9.9.8"
+
+			err := AssertConsistentImageTag(strategy, "v9.9.9")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-registry/virt-api:v9.9.8"))
+			Expect(err.Error()).To(ContainSubstring(strategy.deployments[0].Name))
+		})
+	})
+
+	Context("discovery", func() {
+
+		newDiscovery := func(exceptKinds ...string) *fakediscovery.FakeDiscovery {
+			except := map[string]bool{}
+			for _, kind := range exceptKinds {
+				except[kind] = true
+			}
+			lists := map[string]*metav1.APIResourceList{}
+			for kind, resource := range strategyKindResources {
+				groupVersion := resource.GroupVersion()
+				if except[kind] {
+					continue
+				}
+				list, ok := lists[groupVersion.String()]
+				if !ok {
+					list = &metav1.APIResourceList{GroupVersion: groupVersion.String()}
+					lists[groupVersion.String()] = list
+				}
+				list.APIResources = append(list.APIResources, metav1.APIResource{Kind: kind})
+			}
+
+			discovery := &fakediscovery.FakeDiscovery{Fake: &testing.Fake{}}
+			for _, list := range lists {
+				discovery.Resources = append(discovery.Resources, list)
+			}
+			return discovery
+		}
+
+		It("should accept a cluster serving all kinds", func() {
+			Expect(ValidateAgainstDiscovery(newStrategy("fake-registry", "v9.9.9"), newDiscovery())).To(Succeed())
+		})
+
+		It("should report the kinds the cluster does not serve", func() {
+			err := ValidateAgainstDiscovery(newStrategy("fake-registry", "v9.9.9"), newDiscovery("Route", "ServiceMonitor"))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("kind Route of route.openshift.io/v1 is not served"))
+			Expect(err.Error()).To(ContainSubstring("kind ServiceMonitor of monitoring.coreos.com/v1 is not served"))
+			Expect(err.Error()).ToNot(ContainSubstring("Deployment"))
+		})
+	})
+
+	Context("service selectors", func() {
+
+		It("should accept the generated services", func() {
+			Expect(ValidateServiceSelectors(newStrategy("fake-registry", "v9.9.9"))).To(Succeed())
+		})
+
+		It("should reject services with an empty selector or an empty selector key", func() {
+			strategy := newStrategy("fake-registry", "v9.9.9")
+			strategy.services[0].Spec.Selector = nil
+			strategy.services[1].Spec.Selector = map[string]string{"": "virt-api"}
+
+			err := ValidateServiceSelectors(strategy)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("Service %s/%s has an empty selector", namespace, strategy.services[0].Name)))
+			Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("Service %s/%s has a selector with an empty key", namespace, strategy.services[1].Name)))
+		})
+
+		It("should accept ExternalName and explicitly selectorless services", func() {
+			strategy := newStrategy("fake-registry", "v9.9.9")
+			strategy.services[0].Spec.Selector = nil
+			strategy.services[0].Spec.Type = corev1.ServiceTypeExternalName
+			strategy.services[1].Spec.Selector = nil
+			strategy.services[1].Annotations = map[string]string{SelectorlessServiceAnnotation: ""}
+
+			Expect(ValidateServiceSelectors(strategy)).To(Succeed())
+		})
+	})
+
+	Context("workload selectors", func() {
+
+		It("should accept the generated workloads", func() {
+			Expect(ValidateWorkloadSelectors(newStrategy("fake-registry", "v9.9.9"))).To(Succeed())
+		})
+
+		It("should reject a Deployment whose selector does not match its pod labels", func() {
+			strategy := newStrategy("fake-registry", "v9.9.9")
+			deployment := strategy.deployments[0]
+			deployment.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"kubevirt.io": "mismatch"}}
+
+			err := ValidateWorkloadSelectors(strategy)
+			Expect(err).To(MatchError(ContainSubstring(fmt.Sprintf("Deployment %s/%s selector kubevirt.io=mismatch does not match its pod template labels", namespace, deployment.Name))))
+		})
+
+		It("should check a selector with only matchExpressions", func() {
+			strategy := newStrategy("fake-registry", "v9.9.9")
+			deployment := strategy.deployments[0]
+			deployment.Spec.Selector = &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{
+					Key:      "kubevirt.io",
+					Operator: metav1.LabelSelectorOpIn,
+					Values:   []string{deployment.Spec.Template.Labels["kubevirt.io"]},
+				}},
+			}
+			Expect(ValidateWorkloadSelectors(strategy)).To(Succeed())
+
+			deployment.Spec.Selector.MatchExpressions[0].Values = []string{"mismatch"}
+			err := ValidateWorkloadSelectors(strategy)
+			Expect(err).To(MatchError(ContainSubstring(fmt.Sprintf("Deployment %s/%s selector kubevirt.io in (mismatch) does not match its pod template labels", namespace, deployment.Name))))
+		})
+
+		It("should reject an invalid selector", func() {
+			strategy := newStrategy("fake-registry", "v9.9.9")
+			daemonSet := strategy.daemonSets[0]
+			daemonSet.Spec.Selector = &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "kubevirt.io", Operator: "Near"}},
+			}
+
+			err := ValidateWorkloadSelectors(strategy)
+			Expect(err).To(MatchError(ContainSubstring(fmt.Sprintf("DaemonSet %s/%s has an invalid selector", namespace, daemonSet.Name))))
+		})
+
+		It("should reject a DaemonSet without a selector", func() {
+			strategy := newStrategy("fake-registry", "v9.9.9")
+			daemonSet := strategy.daemonSets[0]
+			daemonSet.Spec.Selector = nil
+
+			err := ValidateWorkloadSelectors(strategy)
+			Expect(err).To(MatchError(fmt.Sprintf("DaemonSet %s/%s has an empty selector", namespace, daemonSet.Name)))
+		})
+	})
+
+	Context("object namespaces", func() {
+
+		It("should accept the generated objects with the monitoring namespace", func() {
+			Expect(ValidateObjectNamespaces(newStrategy("fake-registry", "v9.9.9"), namespace, "openshift-monitoring")).To(Succeed())
+		})
+
+		It("should reject a namespaced object without a namespace", func() {
+			strategy := newStrategy("fake-registry", "v9.9.9")
+			service := strategy.services[0]
+			service.Namespace = ""
+
+			err := ValidateObjectNamespaces(strategy, namespace, "openshift-monitoring")
+			Expect(err).To(MatchError(fmt.Sprintf("Service %s has no namespace, expected %s", service.Name, namespace)))
+		})
+
+		It("should reject objects in other namespaces and cluster-scoped objects with a namespace", func() {
+			strategy := newStrategy("fake-registry", "v9.9.9")
+			strategy.deployments[0].Namespace = "other"
+			strategy.crds[0].Namespace = namespace
+
+			err := ValidateObjectNamespaces(strategy, namespace, "openshift-monitoring")
+			Expect(err).To(MatchError(ContainSubstring(fmt.Sprintf("Deployment other/%s is not in the operator namespace %s", strategy.deployments[0].Name, namespace))))
+			Expect(err).To(MatchError(ContainSubstring(fmt.Sprintf("cluster-scoped CustomResourceDefinition %s has namespace %s", strategy.crds[0].Name, namespace))))
+		})
+	})
+
+	Context("container ports", func() {
+
+		It("should accept the generated workloads", func() {
+			Expect(ValidateContainerPorts(newStrategy("fake-registry", "v9.9.9"))).To(Succeed())
+		})
+
+		It("should reject two containers on the same port", func() {
+			strategy := newStrategy("fake-registry", "v9.9.9")
+			podSpec := &strategy.deployments[0].Spec.Template.Spec
+			container := podSpec.Containers[0]
+			Expect(container.Ports).ToNot(BeEmpty())
+			podSpec.Containers = append(podSpec.Containers, corev1.Container{
+				Name:  "sidecar",
+				Ports: []corev1.ContainerPort{{ContainerPort: container.Ports[0].ContainerPort}},
+			})
+
+			err := ValidateContainerPorts(strategy)
+			Expect(err).To(MatchError(fmt.Sprintf("Deployment %s/%s containers %s and sidecar both declare port %d/TCP", namespace, strategy.deployments[0].Name, container.Name, container.Ports[0].ContainerPort)))
+		})
+
+		It("should accept the same port with different protocols", func() {
+			strategy := newStrategy("fake-registry", "v9.9.9")
+			podSpec := &strategy.deployments[0].Spec.Template.Spec
+			podSpec.Containers = append(podSpec.Containers, corev1.Container{
+				Name:  "sidecar",
+				Ports: []corev1.ContainerPort{{ContainerPort: podSpec.Containers[0].Ports[0].ContainerPort, Protocol: corev1.ProtocolUDP}},
+			})
+
+			Expect(ValidateContainerPorts(strategy)).To(Succeed())
+		})
+	})
+
+	Context("wildcard RBAC", func() {
+
+		It("should accept the generated roles", func() {
+			Expect(AssertNoWildcardRBAC(newStrategy("fake-registry", "v9.9.9"))).To(Succeed())
+		})
+
+		It("should reject wildcard rules which are not allowed", func() {
+			strategy := newStrategy("fake-registry", "v9.9.9")
+			strategy.clusterRoles = append(strategy.clusterRoles, &rbacv1.ClusterRole{
+				ObjectMeta: metav1.ObjectMeta{Name: "too-broad"},
+				Rules: []rbacv1.PolicyRule{
+					{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"*"}},
+					{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"get"}},
+				},
+			})
+			strategy.roles = append(strategy.roles, &rbacv1.Role{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "too-broad"},
+				Rules: []rbacv1.PolicyRule{
+					{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"get"}},
+				},
+			})
+
+			err := AssertNoWildcardRBAC(strategy)
+			Expect(err).To(MatchError(fmt.Sprintf(`[ClusterRole too-broad grants "*" verbs for API groups [apps], Role %s/too-broad grants "*" apiGroups, resources for API groups [*]]`, namespace)))
+		})
+
+		It("should reject wildcards of an allowed role outside of its allowed API groups", func() {
+			strategy := newStrategy("fake-registry", "v9.9.9")
+			for _, clusterRole := range strategy.clusterRoles {
+				if clusterRole.Name == components.ControllerServiceAccountName {
+					clusterRole.Rules = append(clusterRole.Rules, rbacv1.PolicyRule{
+						APIGroups: []string{"kubevirt.io", "apps"}, Resources: []string{"*"}, Verbs: []string{"*"},
+					})
+				}
+			}
+
+			err := AssertNoWildcardRBAC(strategy)
+			Expect(err).To(MatchError(`ClusterRole kubevirt-controller grants "*" resources, verbs for API groups [kubevirt.io apps]`))
+		})
+	})
+
+	Context("config map keys", func() {
+
+		It("should accept the generated config maps", func() {
+			Expect(ValidateConfigMapKeys(newStrategy("fake-registry", "v9.9.9"))).To(Succeed())
+		})
+
+		It("should reject invalid data and binaryData keys", func() {
+			strategy := newStrategy("fake-registry", "v9.9.9")
+			strategy.configMaps = append(strategy.configMaps, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "invalid-keys"},
+				Data:       map[string]string{"valid.key": "", "invalid/key": ""},
+				BinaryData: map[string][]byte{"invalid key": nil},
+			})
+
+			err := ValidateConfigMapKeys(strategy)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("ConfigMap %s/invalid-keys has an invalid key %q", namespace, "invalid/key")))
+			Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("ConfigMap %s/invalid-keys has an invalid key %q", namespace, "invalid key")))
+			Expect(err.Error()).ToNot(ContainSubstring("valid.key"))
+		})
+
+		It("should reject keys used in data and binaryData", func() {
+			strategy := newStrategy("fake-registry", "v9.9.9")
+			strategy.configMaps = append(strategy.configMaps, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "duplicate-keys"},
+				Data:       map[string]string{"key": ""},
+				BinaryData: map[string][]byte{"key": nil},
+			})
+
+			Expect(ValidateConfigMapKeys(strategy)).To(MatchError(ContainSubstring("key \"key\" is used in data and binaryData")))
+		})
+	})
+
+	Context("metrics service labels", func() {
+
+		var strategy *Strategy
+		var service *corev1.Service
+
+		BeforeEach(func() {
+			strategy = newStrategy("fake-registry", "v9.9.9")
+			service = metricsService(strategy)
+			Expect(service).ToNot(BeNil())
+		})
+
+		It("should accept the generated metrics service", func() {
+			Expect(ValidateMetricsServiceLabels(strategy)).To(Succeed())
+			Expect(RepairMetricsServiceLabels(strategy)).To(BeFalse())
+		})
+
+		It("should detect and repair a missing scrape label", func() {
+			delete(service.Labels, "prometheus.kubevirt.io")
+
+			err := ValidateMetricsServiceLabels(strategy)
+			Expect(err).To(MatchError(fmt.Sprintf("metrics service %s/%s is missing label prometheus.kubevirt.io=true", namespace, components.PrometheusServiceName)))
+
+			Expect(RepairMetricsServiceLabels(strategy)).To(BeTrue())
+			Expect(service.Labels).To(HaveKeyWithValue("prometheus.kubevirt.io", "true"))
+			Expect(ValidateMetricsServiceLabels(strategy)).To(Succeed())
+		})
+
+		It("should detect a wrong scrape label value", func() {
+			service.Labels["prometheus.kubevirt.io"] = "false"
+
+			err := ValidateMetricsServiceLabels(strategy)
+			Expect(err).To(MatchError(fmt.Sprintf("metrics service %s/%s has label prometheus.kubevirt.io=false, expected true", namespace, components.PrometheusServiceName)))
+		})
+
+		It("should report a missing metrics service", func() {
+			strategy.services = nil
+
+			Expect(ValidateMetricsServiceLabels(strategy)).To(MatchError("metrics service kubevirt-prometheus-metrics is missing"))
+			Expect(RepairMetricsServiceLabels(strategy)).To(BeFalse())
+		})
+	})
+
+	Context("annotation keys", func() {
+
+		It("should accept the generated objects", func() {
+			Expect(ValidateAnnotationKeys(newStrategy("fake-registry", "v9.9.9"))).To(Succeed())
+		})
+
+		It("should report malformed keys with their object", func() {
+			strategy := newStrategy("fake-registry", "v9.9.9")
+			service := strategy.services[0]
+			service.Annotations = map[string]string{"example.org/valid": "", "-invalid.org/key": ""}
+			deployment := strategy.deployments[0]
+			deployment.Spec.Template.Annotations = map[string]string{"example.org/" + strings.Repeat("a", 64): ""}
+
+			err := ValidateAnnotationKeys(strategy)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("Service %s/%s has an invalid annotation key %q", namespace, service.Name, "-invalid.org/key")))
+			Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("Deployment %s/%s pod template has an invalid annotation key %q", namespace, deployment.Name, "example.org/"+strings.Repeat("a", 64))))
+			Expect(err.Error()).ToNot(ContainSubstring("example.org/valid"))
+		})
+	})
+
+	Context("CRD names", func() {
+
+		It("should accept the generated CRDs", func() {
+			Expect(ValidateCRDNames(newStrategy("fake-registry", "v9.9.9"))).To(Succeed())
+		})
+
+		It("should reject a CRD whose name does not match its plural name and group", func() {
+			strategy := newStrategy("fake-registry", "v9.9.9")
+			crd := strategy.crds[0]
+			crd.Spec.Names.Plural = "mismatches"
+
+			err := ValidateCRDNames(strategy)
+			Expect(err).To(MatchError(fmt.Sprintf("CustomResourceDefinition %s does not match its plural name \"mismatches\" and group %q, expected mismatches.%s", crd.Name, crd.Spec.Group, crd.Spec.Group)))
+		})
+	})
+
+	Context("status subresources", func() {
+
+		It("should accept the generated CRDs", func() {
+			Expect(ValidateStatusSubresources(newStrategy("fake-registry", "v9.9.9"))).To(Succeed())
+		})
+
+		It("should report a VirtualMachine CRD version without the status subresource", func() {
+			strategy := newStrategy("fake-registry", "v9.9.9")
+			var crd *extv1.CustomResourceDefinition
+			for _, c := range strategy.crds {
+				if c.Name == components.VIRTUALMACHINE {
+					crd = c
+				}
+			}
+			Expect(crd).ToNot(BeNil())
+			crd.Spec.Versions[0].Subresources = nil
+
+			err := ValidateStatusSubresources(strategy)
+			Expect(err).To(MatchError(fmt.Sprintf("CustomResourceDefinition %s version %s does not declare the status subresource", crd.Name, crd.Spec.Versions[0].Name)))
+		})
+
+		It("should not require the status subresource on the VirtualMachineInstance CRD", func() {
+			strategy := newStrategy("fake-registry", "v9.9.9")
+			for _, crd := range strategy.crds {
+				if crd.Name == components.VIRTUALMACHINEINSTANCE {
+					Expect(crd.Spec.Versions[0].Subresources).To(BeNil())
+				}
+			}
+			Expect(ValidateStatusSubresources(strategy)).To(Succeed())
+		})
+	})
+
+	Context("service target ports", func() {
+
+		It("should accept the generated services", func() {
+			Expect(ValidateServiceTargetPorts(newStrategy("fake-registry", "v9.9.9"))).To(Succeed())
+		})
+
+		It("should report a named target port which the selected pods do not declare", func() {
+			strategy := newStrategy("fake-registry", "v9.9.9")
+			deployment := strategy.deployments[0]
+			strategy.services = append(strategy.services, &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "undeclared"},
+				Spec: corev1.ServiceSpec{
+					Selector: deployment.Spec.Template.Labels,
+					Ports: []corev1.ServicePort{
+						{Port: 8080, TargetPort: intstr.FromString("undeclared")},
+						{Port: 8443, TargetPort: intstr.FromInt(8443)},
+					},
+				},
+			})
+
+			err := ValidateServiceTargetPorts(strategy)
+			Expect(err).To(MatchError(fmt.Sprintf(`Service %s/undeclared port 8080 targets port "undeclared" which no container of Deployment %s/%s declares`, namespace, namespace, deployment.Name)))
+		})
+	})
+
+	Context("pod disruption budgets", func() {
+
+		It("should accept the generated deployments", func() {
+			Expect(ValidatePodDisruptionBudgets(newStrategy("fake-registry", "v9.9.9"))).To(Succeed())
+		})
+
+		It("should report a multi-replica deployment without a budget", func() {
+			strategy := newStrategy("fake-registry", "v9.9.9")
+			replicas, single := int32(3), int32(1)
+			strategy.deployments = append(strategy.deployments,
+				&appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "virt-extra"},
+					Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+				},
+				&appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "virt-single"},
+					Spec:       appsv1.DeploymentSpec{Replicas: &single},
+				},
+			)
+
+			err := ValidatePodDisruptionBudgets(strategy)
+			Expect(err).To(MatchError(fmt.Sprintf("Deployment %s/virt-extra has 3 replicas but no PodDisruptionBudget", namespace)))
+		})
+	})
+
+	Context("service overlap", func() {
+
+		It("should accept the generated services", func() {
+			Expect(ValidateServiceOverlap(newStrategy("fake-registry", "v9.9.9"))).To(Succeed())
+		})
+
+		It("should report services selecting the same pods on the same port", func() {
+			strategy := newStrategy("fake-registry", "v9.9.9")
+			var apiService *corev1.Service
+			for _, service := range strategy.services {
+				if service.Name == "virt-api" {
+					apiService = service
+				}
+			}
+			Expect(apiService).ToNot(BeNil())
+			overlapping := apiService.DeepCopy()
+			overlapping.Name = "virt-api-overlap"
+			overlapping.Spec.Selector["extra"] = "label"
+			strategy.services = append(strategy.services, overlapping)
+
+			err := ValidateServiceOverlap(strategy)
+			Expect(err).To(MatchError(fmt.Sprintf("Services %s/virt-api and %s/virt-api-overlap select the same pods on port 443/TCP", namespace, namespace)))
+		})
+
+		It("should accept services selecting the same pods on different ports", func() {
+			strategy := newStrategy("fake-registry", "v9.9.9")
+			overlapping := strategy.services[0].DeepCopy()
+			overlapping.Name = "other-port"
+			for i := range overlapping.Spec.Ports {
+				overlapping.Spec.Ports[i].Port += 1000
+			}
+			strategy.services = append(strategy.services, overlapping)
+
+			Expect(ValidateServiceOverlap(strategy)).To(Succeed())
+		})
+	})
+
+	Context("RoleBinding subjects", func() {
+
+		It("should accept the generated bindings with the monitoring namespace", func() {
+			Expect(ValidateRoleBindingSubjects(newStrategy("fake-registry", "v9.9.9"), namespace, "openshift-monitoring")).To(Succeed())
+		})
+
+		It("should reject a subject in a namespace which is not known to exist", func() {
+			strategy := newStrategy("fake-registry", "v9.9.9")
+			strategy.roleBindings = append(strategy.roleBindings, &rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "cross-namespace"},
+				Subjects: []rbacv1.Subject{
+					{Kind: rbacv1.ServiceAccountKind, Namespace: "missing", Name: "reader"},
+				},
+			})
+
+			err := ValidateRoleBindingSubjects(strategy, namespace, "openshift-monitoring")
+			Expect(err).To(MatchError(fmt.Sprintf("RoleBinding %s/cross-namespace binds ServiceAccount missing/reader in a namespace which is not created by the install strategy", namespace)))
+		})
+
+		It("should accept a subject in a namespace created by the strategy", func() {
+			strategy := newStrategy("fake-registry", "v9.9.9")
+			strategy.namespaces = append(strategy.namespaces, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "created"}})
+			strategy.roleBindings = append(strategy.roleBindings, &rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "cross-namespace"},
+				Subjects: []rbacv1.Subject{
+					{Kind: rbacv1.ServiceAccountKind, Namespace: "created", Name: "reader"},
+				},
+			})
+
+			Expect(ValidateRoleBindingSubjects(strategy, namespace, "openshift-monitoring")).To(Succeed())
+		})
+	})
+
+	Context("deployment identifier", func() {
+
+		It("should accept generated objects without an identifier and objects with the expected one", func() {
+			strategy := newStrategy("fake-registry", "v9.9.9")
+			strategy.services[0].Annotations = map[string]string{v1.InstallStrategyIdentifierAnnotation: "expected-id"}
+
+			Expect(AssertDeploymentIdentifier(strategy, "expected-id")).To(Succeed())
+		})
+
+		It("should reject an object with the identifier of another deployment", func() {
+			strategy := newStrategy("fake-registry", "v9.9.9")
+			service := strategy.services[0]
+			service.Annotations = map[string]string{v1.InstallStrategyIdentifierAnnotation: "stale-id"}
+
+			err := AssertDeploymentIdentifier(strategy, "expected-id")
+			Expect(err).To(MatchError(fmt.Sprintf("Service %s/%s carries install strategy identifier \"stale-id\", expected \"expected-id\"", namespace, service.Name)))
+		})
+	})
+})
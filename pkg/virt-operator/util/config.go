@@ -29,9 +29,14 @@ import (
 	"regexp"
 	"runtime"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
 	k8sv1 "k8s.io/api/core/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	v1 "kubevirt.io/api/core/v1"
 	clientutil "kubevirt.io/client-go/util"
@@ -101,6 +106,112 @@ const (
 	// lookup key in AdditionalProperties
 	AdditionalPropertiesPersistentReservationEnabled = "PersistentReservationEnabled"
 
+	// lookup key in AdditionalProperties
+	AdditionalPropertiesArchitecture = "Architecture"
+
+	// lookup key in AdditionalProperties
+	AdditionalPropertiesHandlerUpdateStrategy = "HandlerUpdateStrategy"
+
+	// lookup key in AdditionalProperties, JSON encoded map of storage class defaults
+	AdditionalPropertiesStorageClassDefaults = "StorageClassDefaults"
+
+	// lookup key in AdditionalProperties, JSON encoded list of extra volumes for virt-handler
+	AdditionalPropertiesHandlerExtraVolumes = "HandlerExtraVolumes"
+
+	// lookup key in AdditionalProperties, JSON encoded list of extra volume mounts for virt-handler
+	AdditionalPropertiesHandlerExtraVolumeMounts = "HandlerExtraVolumeMounts"
+
+	// lookup key in AdditionalProperties
+	AdditionalPropertiesExtendedPrinterColumns = "ExtendedPrinterColumns"
+
+	// lookup key in AdditionalProperties, a duration like "10m"
+	AdditionalPropertiesResyncInterval = "ResyncInterval"
+
+	// the shortest accepted interval between full reconciles
+	MinResyncInterval = time.Minute
+
+	// lookup key in AdditionalProperties, JSON encoded map of "Kind/name" to JSON patches
+	AdditionalPropertiesObjectPatches = "ObjectPatches"
+
+	// lookup key in AdditionalProperties, finalizer added to generated objects
+	AdditionalPropertiesObjectFinalizer = "ObjectFinalizer"
+
+	// lookup key in AdditionalProperties
+	AdditionalPropertiesApiMetricsTLS = "ApiMetricsTLS"
+
+	// lookup key in AdditionalProperties
+	AdditionalPropertiesPatchEvents = "PatchEvents"
+
+	// lookup key in AdditionalProperties, a JSON string array with the preStop command of the Deployments
+	AdditionalPropertiesDeploymentPreStopCommand = "DeploymentPreStopCommand"
+
+	// lookup key in AdditionalProperties, the terminationGracePeriodSeconds of the Deployments
+	AdditionalPropertiesDeploymentTerminationGracePeriod = "DeploymentTerminationGracePeriod"
+
+	// lookup key in AdditionalProperties
+	AdditionalPropertiesImmutableInstallStrategy = "ImmutableInstallStrategy"
+
+	// lookup key in AdditionalProperties, a JSON object of CRD name to extra short names
+	AdditionalPropertiesCRDShortNames = "CRDShortNames"
+
+	// lookup key in AdditionalProperties, a JSON object of CRD name to extra categories
+	AdditionalPropertiesCRDCategories = "CRDCategories"
+
+	// lookup key in AdditionalProperties
+	AdditionalPropertiesCreateNamespace = "CreateNamespace"
+
+	// lookup key in AdditionalProperties, a JSON object of component name to service account name
+	AdditionalPropertiesServiceAccountNames = "ServiceAccountNames"
+
+	// lookup key in AdditionalProperties, a JSON object of annotations added to LoadBalancer services
+	AdditionalPropertiesLoadBalancerServiceAnnotations = "LoadBalancerServiceAnnotations"
+
+	// lookup key in AdditionalProperties, a JSON object of annotations added to the generated RBAC objects
+	AdditionalPropertiesRBACAnnotations = "RBACAnnotations"
+
+	// lookup key in AdditionalProperties, a node count or a percentage like "10%"
+	AdditionalPropertiesHandlerMaxUnavailable = "HandlerMaxUnavailable"
+
+	// lookup key in AdditionalProperties, a JSON object of component name to container command and args overrides
+	AdditionalPropertiesContainerOverrides = "ContainerOverrides"
+
+	// lookup key in AdditionalProperties, a pod count or a percentage like "25%"
+	AdditionalPropertiesDeploymentMaxSurge = "DeploymentMaxSurge"
+
+	// lookup key in AdditionalProperties, a pod count or a percentage like "25%"
+	AdditionalPropertiesDeploymentMaxUnavailable = "DeploymentMaxUnavailable"
+
+	// lookup key in AdditionalProperties, a JSON object of service name to ClientIP session affinity timeout in seconds
+	AdditionalPropertiesServiceSessionAffinity = "ServiceSessionAffinity"
+
+	// lookup key in AdditionalProperties, Patch or Replace
+	AdditionalPropertiesReconcileMode = "ReconcileMode"
+
+	// lookup key in AdditionalProperties
+	AdditionalPropertiesHandlerHostNetwork = "HandlerHostNetwork"
+
+	// lookup key in AdditionalProperties, a JSON object of config map name to an object of key to base64 encoded value
+	AdditionalPropertiesConfigMapBinaryData = "ConfigMapBinaryData"
+
+	// lookup key in AdditionalProperties
+	AdditionalPropertiesProductionMode = "ProductionMode"
+
+	// lookup key in AdditionalProperties, allows the "latest" image tag in production mode
+	AdditionalPropertiesAllowLatestTag = "AllowLatestTag"
+
+	// lookup key in AdditionalProperties, a JSON object of Deployment name to a list of readiness gate condition types
+	AdditionalPropertiesReadinessGates = "ReadinessGates"
+
+	// lookup key in AdditionalProperties, a JSON object of component name to the startup probe of its container
+	AdditionalPropertiesStartupProbes = "StartupProbes"
+
+	// lookup key in AdditionalProperties, a node count or a percentage like "10%"
+	AdditionalPropertiesHandlerMaxSurge = "HandlerMaxSurge"
+
+	// prefix of the KubeVirt CR annotations which set the additional properties without a field in the KubeVirt spec,
+	// e.g. deployment-config.kubevirt.io/HandlerHostNetwork: "true"
+	DeploymentConfigAnnotationPrefix = "deployment-config.kubevirt.io/"
+
 	// account to use if one is not explicitly named
 	DefaultMonitorAccount = "prometheus-k8s"
 
@@ -118,6 +229,44 @@ const (
 	PassthroughEnvPrefix = "KV_IO_EXTRA_ENV_"
 )
 
+// annotationProperties are the additional properties which can be set with DeploymentConfigAnnotationPrefix
+// annotations on the KubeVirt CR, mapped to whether they are flags
+var annotationProperties = map[string]bool{
+	AdditionalPropertiesArchitecture:                     false,
+	AdditionalPropertiesHandlerUpdateStrategy:            false,
+	AdditionalPropertiesStorageClassDefaults:             false,
+	AdditionalPropertiesHandlerExtraVolumes:              false,
+	AdditionalPropertiesHandlerExtraVolumeMounts:         false,
+	AdditionalPropertiesExtendedPrinterColumns:           true,
+	AdditionalPropertiesResyncInterval:                   false,
+	AdditionalPropertiesObjectPatches:                    false,
+	AdditionalPropertiesObjectFinalizer:                  false,
+	AdditionalPropertiesApiMetricsTLS:                    true,
+	AdditionalPropertiesPatchEvents:                      true,
+	AdditionalPropertiesDeploymentPreStopCommand:         false,
+	AdditionalPropertiesDeploymentTerminationGracePeriod: false,
+	AdditionalPropertiesImmutableInstallStrategy:         true,
+	AdditionalPropertiesCRDShortNames:                    false,
+	AdditionalPropertiesCRDCategories:                    false,
+	AdditionalPropertiesCreateNamespace:                  true,
+	AdditionalPropertiesServiceAccountNames:              false,
+	AdditionalPropertiesLoadBalancerServiceAnnotations:   false,
+	AdditionalPropertiesRBACAnnotations:                  false,
+	AdditionalPropertiesHandlerMaxUnavailable:            false,
+	AdditionalPropertiesContainerOverrides:               false,
+	AdditionalPropertiesDeploymentMaxSurge:               false,
+	AdditionalPropertiesDeploymentMaxUnavailable:         false,
+	AdditionalPropertiesServiceSessionAffinity:           false,
+	AdditionalPropertiesReconcileMode:                    false,
+	AdditionalPropertiesHandlerHostNetwork:               true,
+	AdditionalPropertiesConfigMapBinaryData:              false,
+	AdditionalPropertiesProductionMode:                   true,
+	AdditionalPropertiesAllowLatestTag:                   true,
+	AdditionalPropertiesReadinessGates:                   false,
+	AdditionalPropertiesStartupProbes:                    false,
+	AdditionalPropertiesHandlerMaxSurge:                  false,
+}
+
 // DefaultMonitorNamespaces holds a set of well known prometheus-operator namespaces.
 // Ordering in the list matters. First entries have precedence.
 var DefaultMonitorNamespaces = []string{
@@ -219,6 +368,12 @@ func GetTargetConfigFromKVWithEnvVarManager(kv *v1.KubeVirt, envVarManager EnvVa
 			}
 		}
 	}
+	for key, value := range getKVMapFromAnnotations(kv.Annotations) {
+		// fields of the spec take precedence
+		if _, exists := additionalProperties[key]; !exists {
+			additionalProperties[key] = value
+		}
+	}
 	// don't use status.target* here, as that is always set, but we need to know if it was set by the spec and with that
 	// overriding shasums from env vars
 	return getConfig(kv.Spec.ImageRegistry,
@@ -228,6 +383,101 @@ func GetTargetConfigFromKVWithEnvVarManager(kv *v1.KubeVirt, envVarManager EnvVa
 		envVarManager)
 }
 
+// ResolveConfig returns the target config of the KubeVirt CR with all defaults applied. Unlike GetTargetConfigFromKV
+// it fails for invalid additional properties, which would otherwise be ignored or only fail while generating the
+// install strategy.
+func ResolveConfig(kv *v1.KubeVirt) (*KubeVirtDeploymentConfig, error) {
+	config := GetTargetConfigFromKV(kv)
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// Validate returns an error for every additional property with an invalid value
+func (c *KubeVirtDeploymentConfig) Validate() error {
+	var errs []error
+
+	switch policy := c.GetImagePullPolicy(); policy {
+	case k8sv1.PullAlways, k8sv1.PullIfNotPresent, k8sv1.PullNever:
+	default:
+		errs = append(errs, fmt.Errorf("invalid image pull policy %q", policy))
+	}
+
+	if interval, err := c.GetResyncInterval(); err != nil {
+		errs = append(errs, err)
+	} else if interval != 0 && interval < MinResyncInterval {
+		errs = append(errs, fmt.Errorf("resync interval %s is shorter than the minimum of %s", interval, MinResyncInterval))
+	}
+
+	if strategyType, ok := c.AdditionalProperties[AdditionalPropertiesHandlerUpdateStrategy]; ok &&
+		strategyType != string(appsv1.RollingUpdateDaemonSetStrategyType) && strategyType != string(appsv1.OnDeleteDaemonSetStrategyType) {
+		errs = append(errs, fmt.Errorf("invalid virt-handler update strategy %q", strategyType))
+	}
+
+	if _, err := c.GetHandlerRollingUpdate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if _, err := c.GetDeploymentRollingUpdate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	for _, key := range sortedFlags() {
+		if value, ok := c.AdditionalProperties[key]; ok && value != "" {
+			errs = append(errs, fmt.Errorf("invalid value %q for %s, expected true or false", value, key))
+		}
+	}
+
+	if mode := c.GetReconcileMode(); mode != "" && mode != "Patch" && mode != "Replace" {
+		errs = append(errs, fmt.Errorf("invalid reconcile mode %q", mode))
+	}
+
+	// images pinned by digest are reproducible regardless of the tag
+	if c.ProductionModeEnabled() && !c.LatestTagAllowed() && !c.UseShasums() && c.GetKubeVirtVersion() == "latest" {
+		errs = append(errs, fmt.Errorf("image tag \"latest\" is not allowed in production mode"))
+	}
+
+	if s, ok := c.AdditionalProperties[AdditionalPropertiesDeploymentTerminationGracePeriod]; ok && c.GetDeploymentTerminationGracePeriod() == nil {
+		errs = append(errs, fmt.Errorf("invalid deployment termination grace period %q", s))
+	}
+
+	jsonProperties := map[string]interface{}{
+		AdditionalPropertiesPullSecrets:                    &[]k8sv1.LocalObjectReference{},
+		AdditionalPropertiesStorageClassDefaults:           &map[string]string{},
+		AdditionalPropertiesHandlerExtraVolumes:            &[]k8sv1.Volume{},
+		AdditionalPropertiesHandlerExtraVolumeMounts:       &[]k8sv1.VolumeMount{},
+		AdditionalPropertiesObjectPatches:                  &map[string]string{},
+		AdditionalPropertiesDeploymentPreStopCommand:       &[]string{},
+		AdditionalPropertiesCRDShortNames:                  &map[string][]string{},
+		AdditionalPropertiesCRDCategories:                  &map[string][]string{},
+		AdditionalPropertiesServiceAccountNames:            &map[string]string{},
+		AdditionalPropertiesLoadBalancerServiceAnnotations: &map[string]string{},
+		AdditionalPropertiesRBACAnnotations:                &map[string]string{},
+		AdditionalPropertiesContainerOverrides:             &map[string]ContainerOverride{},
+		AdditionalPropertiesServiceSessionAffinity:         &map[string]int32{},
+		AdditionalPropertiesConfigMapBinaryData:            &map[string]map[string][]byte{},
+		AdditionalPropertiesReadinessGates:                 &map[string][]string{},
+		AdditionalPropertiesStartupProbes:                  &map[string]k8sv1.Probe{},
+	}
+	keys := make([]string, 0, len(jsonProperties))
+	for key := range jsonProperties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		s, ok := c.AdditionalProperties[key]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal([]byte(s), jsonProperties[key]); err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s: %v", key, err))
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
 // retrieve imagePrefix from an existing deployment config (which is stored as JSON)
 func getImagePrefixFromDeploymentConfig(deploymentConfig string) (string, bool, error) {
 	var obj interface{}
@@ -267,6 +517,41 @@ func getKVMapFromSpec(spec v1.KubeVirtSpec) map[string]string {
 	return kvMap
 }
 
+// getKVMapFromAnnotations returns the additional properties set by DeploymentConfigAnnotationPrefix annotations.
+// Flags are set by "true" and left out by "false", any other value is kept for Validate to report it.
+func getKVMapFromAnnotations(annotations map[string]string) map[string]string {
+	kvMap := make(map[string]string)
+	for key, isFlag := range annotationProperties {
+		value, ok := annotations[DeploymentConfigAnnotationPrefix+key]
+		if !ok {
+			continue
+		}
+		if isFlag {
+			enabled, err := strconv.ParseBool(value)
+			if err == nil && !enabled {
+				continue
+			}
+			if err == nil {
+				value = ""
+			}
+		}
+		kvMap[key] = value
+	}
+	return kvMap
+}
+
+// sortedFlags returns the flags among the annotationProperties in a stable order
+func sortedFlags() []string {
+	var flags []string
+	for key, isFlag := range annotationProperties {
+		if isFlag {
+			flags = append(flags, key)
+		}
+	}
+	sort.Strings(flags)
+	return flags
+}
+
 func GetOperatorImage() string {
 	return GetOperatorImageWithEnvVarManager(DefaultEnvVarManager)
 }
@@ -617,6 +902,239 @@ func (c *KubeVirtDeploymentConfig) PersistentReservationEnabled() bool {
 	return enabled
 }
 
+func (c *KubeVirtDeploymentConfig) ExtendedPrinterColumnsEnabled() bool {
+	_, enabled := c.AdditionalProperties[AdditionalPropertiesExtendedPrinterColumns]
+	return enabled
+}
+
+// GetResyncInterval returns the interval after which a full reconcile of an installed KubeVirt is forced even without
+// events, or zero if none is forced
+func (c *KubeVirtDeploymentConfig) GetResyncInterval() (time.Duration, error) {
+	value, ok := c.AdditionalProperties[AdditionalPropertiesResyncInterval]
+	if !ok || value == "" {
+		return 0, nil
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid resync interval %q: %v", value, err)
+	}
+	return interval, nil
+}
+
+func (c *KubeVirtDeploymentConfig) GetObjectFinalizer() string {
+	return c.AdditionalProperties[AdditionalPropertiesObjectFinalizer]
+}
+
+func (c *KubeVirtDeploymentConfig) ApiMetricsTLSEnabled() bool {
+	_, enabled := c.AdditionalProperties[AdditionalPropertiesApiMetricsTLS]
+	return enabled
+}
+
+func (c *KubeVirtDeploymentConfig) PatchEventsEnabled() bool {
+	_, enabled := c.AdditionalProperties[AdditionalPropertiesPatchEvents]
+	return enabled
+}
+
+// HandlerHostNetworkEnabled returns whether virt-handler runs in the network namespace of the node
+func (c *KubeVirtDeploymentConfig) HandlerHostNetworkEnabled() bool {
+	_, enabled := c.AdditionalProperties[AdditionalPropertiesHandlerHostNetwork]
+	return enabled
+}
+
+// ProductionModeEnabled returns whether the config has to meet the requirements of a production deployment
+func (c *KubeVirtDeploymentConfig) ProductionModeEnabled() bool {
+	_, enabled := c.AdditionalProperties[AdditionalPropertiesProductionMode]
+	return enabled
+}
+
+// LatestTagAllowed returns whether the "latest" image tag is explicitly allowed in production mode
+func (c *KubeVirtDeploymentConfig) LatestTagAllowed() bool {
+	_, allowed := c.AdditionalProperties[AdditionalPropertiesAllowLatestTag]
+	return allowed
+}
+
+// GetReconcileMode returns how the operator reconciles drifted objects, Patch or Replace, or an empty string for the
+// default
+func (c *KubeVirtDeploymentConfig) GetReconcileMode() string {
+	return c.AdditionalProperties[AdditionalPropertiesReconcileMode]
+}
+
+func (c *KubeVirtDeploymentConfig) GetDeploymentPreStopCommand() []string {
+	var data []string
+	s, ok := c.AdditionalProperties[AdditionalPropertiesDeploymentPreStopCommand]
+	if !ok {
+		return data
+	}
+	if err := json.Unmarshal([]byte(s), &data); err != nil {
+		fmt.Printf("Unable to parse deployment preStop command: %v\n", err)
+		return nil
+	}
+	return data
+}
+
+func (c *KubeVirtDeploymentConfig) GetDeploymentTerminationGracePeriod() *int64 {
+	s, ok := c.AdditionalProperties[AdditionalPropertiesDeploymentTerminationGracePeriod]
+	if !ok {
+		return nil
+	}
+	seconds, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || seconds < 0 {
+		fmt.Printf("Unable to parse deployment termination grace period %q\n", s)
+		return nil
+	}
+	return &seconds
+}
+
+func (c *KubeVirtDeploymentConfig) ImmutableInstallStrategyEnabled() bool {
+	_, enabled := c.AdditionalProperties[AdditionalPropertiesImmutableInstallStrategy]
+	return enabled
+}
+
+func (c *KubeVirtDeploymentConfig) GetCRDShortNames() map[string][]string {
+	return c.getCRDNames(AdditionalPropertiesCRDShortNames)
+}
+
+func (c *KubeVirtDeploymentConfig) GetCRDCategories() map[string][]string {
+	return c.getCRDNames(AdditionalPropertiesCRDCategories)
+}
+
+func (c *KubeVirtDeploymentConfig) getCRDNames(key string) map[string][]string {
+	var data map[string][]string
+	s, ok := c.AdditionalProperties[key]
+	if !ok {
+		return data
+	}
+	if err := json.Unmarshal([]byte(s), &data); err != nil {
+		fmt.Printf("Unable to parse %s: %v\n", key, err)
+		return nil
+	}
+	return data
+}
+
+func (c *KubeVirtDeploymentConfig) CreateNamespaceEnabled() bool {
+	_, enabled := c.AdditionalProperties[AdditionalPropertiesCreateNamespace]
+	return enabled
+}
+
+// GetServiceAccountNames returns the service account name overrides keyed by component name
+func (c *KubeVirtDeploymentConfig) GetServiceAccountNames() map[string]string {
+	var names map[string]string
+	s, ok := c.AdditionalProperties[AdditionalPropertiesServiceAccountNames]
+	if !ok {
+		return names
+	}
+	if err := json.Unmarshal([]byte(s), &names); err != nil {
+		fmt.Printf("Unable to parse service account names: %v\n", err)
+		return nil
+	}
+	return names
+}
+
+// GetLoadBalancerServiceAnnotations returns the extra annotations of the generated LoadBalancer services
+func (c *KubeVirtDeploymentConfig) GetLoadBalancerServiceAnnotations() map[string]string {
+	var annotations map[string]string
+	s, ok := c.AdditionalProperties[AdditionalPropertiesLoadBalancerServiceAnnotations]
+	if !ok {
+		return annotations
+	}
+	if err := json.Unmarshal([]byte(s), &annotations); err != nil {
+		fmt.Printf("Unable to parse load balancer service annotations: %v\n", err)
+		return nil
+	}
+	return annotations
+}
+
+// GetRBACAnnotations returns the extra annotations of the generated ClusterRoles, Roles and their bindings
+func (c *KubeVirtDeploymentConfig) GetRBACAnnotations() map[string]string {
+	var annotations map[string]string
+	s, ok := c.AdditionalProperties[AdditionalPropertiesRBACAnnotations]
+	if !ok {
+		return annotations
+	}
+	if err := json.Unmarshal([]byte(s), &annotations); err != nil {
+		fmt.Printf("Unable to parse RBAC annotations: %v\n", err)
+		return nil
+	}
+	return annotations
+}
+
+// ContainerOverride replaces the command and the args of a component's container. Unset fields keep the generated
+// values.
+type ContainerOverride struct {
+	Command []string `json:"command,omitempty"`
+	Args    []string `json:"args,omitempty"`
+}
+
+// GetContainerOverrides returns the container command and args overrides keyed by component name
+func (c *KubeVirtDeploymentConfig) GetContainerOverrides() map[string]ContainerOverride {
+	var overrides map[string]ContainerOverride
+	s, ok := c.AdditionalProperties[AdditionalPropertiesContainerOverrides]
+	if !ok {
+		return overrides
+	}
+	if err := json.Unmarshal([]byte(s), &overrides); err != nil {
+		fmt.Printf("Unable to parse container overrides: %v\n", err)
+		return nil
+	}
+	return overrides
+}
+
+// GetServiceSessionAffinity returns the ClientIP session affinity timeouts in seconds keyed by service name
+func (c *KubeVirtDeploymentConfig) GetServiceSessionAffinity() map[string]int32 {
+	var timeouts map[string]int32
+	s, ok := c.AdditionalProperties[AdditionalPropertiesServiceSessionAffinity]
+	if !ok {
+		return timeouts
+	}
+	if err := json.Unmarshal([]byte(s), &timeouts); err != nil {
+		fmt.Printf("Unable to parse service session affinity: %v\n", err)
+		return nil
+	}
+	return timeouts
+}
+
+// GetReadinessGates returns the readiness gate condition types of the pods of Deployments, keyed by Deployment name
+func (c *KubeVirtDeploymentConfig) GetReadinessGates() map[string][]string {
+	var gates map[string][]string
+	s, ok := c.AdditionalProperties[AdditionalPropertiesReadinessGates]
+	if !ok {
+		return gates
+	}
+	if err := json.Unmarshal([]byte(s), &gates); err != nil {
+		fmt.Printf("Unable to parse readiness gates: %v\n", err)
+		return nil
+	}
+	return gates
+}
+
+// GetStartupProbes returns the startup probes of the component containers keyed by component name
+func (c *KubeVirtDeploymentConfig) GetStartupProbes() map[string]k8sv1.Probe {
+	var probes map[string]k8sv1.Probe
+	s, ok := c.AdditionalProperties[AdditionalPropertiesStartupProbes]
+	if !ok {
+		return probes
+	}
+	if err := json.Unmarshal([]byte(s), &probes); err != nil {
+		fmt.Printf("Unable to parse startup probes: %v\n", err)
+		return nil
+	}
+	return probes
+}
+
+// GetConfigMapBinaryData returns the binary data of the config maps to generate, keyed by config map name
+func (c *KubeVirtDeploymentConfig) GetConfigMapBinaryData() map[string]map[string][]byte {
+	var data map[string]map[string][]byte
+	s, ok := c.AdditionalProperties[AdditionalPropertiesConfigMapBinaryData]
+	if !ok {
+		return data
+	}
+	if err := json.Unmarshal([]byte(s), &data); err != nil {
+		fmt.Printf("Unable to parse config map binary data: %v\n", err)
+		return nil
+	}
+	return data
+}
+
 func (c *KubeVirtDeploymentConfig) GetMigrationNetwork() *string {
 	value, enabled := c.AdditionalProperties[AdditionalPropertiesMigrationNetwork]
 	if enabled {
@@ -626,6 +1144,177 @@ func (c *KubeVirtDeploymentConfig) GetMigrationNetwork() *string {
 	}
 }
 
+// GetArchitecture returns the node architecture the generated workloads target, or an empty string if they are not pinned to one
+func (c *KubeVirtDeploymentConfig) GetArchitecture() string {
+	return c.AdditionalProperties[AdditionalPropertiesArchitecture]
+}
+
+// GetHandlerUpdateStrategyType returns the update strategy type of the virt-handler DaemonSet, RollingUpdate by default
+func (c *KubeVirtDeploymentConfig) GetHandlerUpdateStrategyType() appsv1.DaemonSetUpdateStrategyType {
+	strategyType := c.AdditionalProperties[AdditionalPropertiesHandlerUpdateStrategy]
+	if strategyType == string(appsv1.OnDeleteDaemonSetStrategyType) {
+		return appsv1.OnDeleteDaemonSetStrategyType
+	}
+	return appsv1.RollingUpdateDaemonSetStrategyType
+}
+
+// GetHandlerMaxUnavailable returns the maxUnavailable of the virt-handler DaemonSet rollout, or nil if it is not set
+func (c *KubeVirtDeploymentConfig) GetHandlerMaxUnavailable() (*intstr.IntOrString, error) {
+	s, ok := c.AdditionalProperties[AdditionalPropertiesHandlerMaxUnavailable]
+	if !ok {
+		return nil, nil
+	}
+
+	value, ok := parseIntOrPercent(s, 1)
+	if !ok {
+		return nil, fmt.Errorf("invalid virt-handler maxUnavailable %q, it has to be a node count of at least 1 or a percentage between 1%% and 100%%", s)
+	}
+	return value, nil
+}
+
+// GetHandlerMaxSurge returns the maxSurge of the virt-handler DaemonSet rollout, or nil if it is not set
+func (c *KubeVirtDeploymentConfig) GetHandlerMaxSurge() (*intstr.IntOrString, error) {
+	s, ok := c.AdditionalProperties[AdditionalPropertiesHandlerMaxSurge]
+	if !ok {
+		return nil, nil
+	}
+
+	value, ok := parseIntOrPercent(s, 0)
+	if !ok {
+		return nil, fmt.Errorf("invalid virt-handler maxSurge %q, it has to be a non-negative node count or a percentage between 0%% and 100%%", s)
+	}
+	return value, nil
+}
+
+// GetHandlerRollingUpdate returns the rolling update parameters of the virt-handler DaemonSet, or nil if neither
+// maxUnavailable nor maxSurge is set. As required for DaemonSets, maxUnavailable is 0 if a maxSurge is set.
+func (c *KubeVirtDeploymentConfig) GetHandlerRollingUpdate() (*appsv1.RollingUpdateDaemonSet, error) {
+	maxUnavailable, err := c.GetHandlerMaxUnavailable()
+	if err != nil {
+		return nil, err
+	}
+	maxSurge, err := c.GetHandlerMaxSurge()
+	if err != nil {
+		return nil, err
+	}
+
+	if maxSurge != nil && maxSurge.String() != "0" && maxSurge.String() != "0%" {
+		if maxUnavailable != nil {
+			return nil, fmt.Errorf("invalid virt-handler rolling update, maxSurge and maxUnavailable must not both be set")
+		}
+		noneUnavailable := intstr.FromInt(0)
+		return &appsv1.RollingUpdateDaemonSet{MaxUnavailable: &noneUnavailable, MaxSurge: maxSurge}, nil
+	}
+	if maxUnavailable == nil {
+		return nil, nil
+	}
+	return &appsv1.RollingUpdateDaemonSet{MaxUnavailable: maxUnavailable}, nil
+}
+
+// GetDeploymentRollingUpdate returns the rolling update parameters of the generated Deployments, or nil if neither
+// maxSurge nor maxUnavailable is set. Unset parameters keep the Kubernetes defaults.
+func (c *KubeVirtDeploymentConfig) GetDeploymentRollingUpdate() (*appsv1.RollingUpdateDeployment, error) {
+	maxSurge, err := c.getRolloutParameter(AdditionalPropertiesDeploymentMaxSurge, "maxSurge")
+	if err != nil {
+		return nil, err
+	}
+	maxUnavailable, err := c.getRolloutParameter(AdditionalPropertiesDeploymentMaxUnavailable, "maxUnavailable")
+	if err != nil {
+		return nil, err
+	}
+	if maxSurge == nil && maxUnavailable == nil {
+		return nil, nil
+	}
+
+	isZero := func(value *intstr.IntOrString) bool {
+		return value != nil && (value.String() == "0" || value.String() == "0%")
+	}
+	if isZero(maxSurge) && isZero(maxUnavailable) {
+		return nil, fmt.Errorf("invalid deployment rolling update, maxSurge and maxUnavailable must not both be zero")
+	}
+	return &appsv1.RollingUpdateDeployment{MaxSurge: maxSurge, MaxUnavailable: maxUnavailable}, nil
+}
+
+// getRolloutParameter returns a count of at least 0 or a percentage between 0% and 100%, or nil if the key is not set
+func (c *KubeVirtDeploymentConfig) getRolloutParameter(key string, name string) (*intstr.IntOrString, error) {
+	s, ok := c.AdditionalProperties[key]
+	if !ok {
+		return nil, nil
+	}
+
+	value, ok := parseIntOrPercent(s, 0)
+	if !ok {
+		return nil, fmt.Errorf("invalid deployment %s %q, it has to be a non-negative count or a percentage between 0%% and 100%%", name, s)
+	}
+	return value, nil
+}
+
+// parseIntOrPercent parses a count of at least min or a percentage between min% and 100%
+func parseIntOrPercent(s string, min int) (*intstr.IntOrString, bool) {
+	value := intstr.Parse(s)
+	if value.Type == intstr.Int {
+		return &value, int(value.IntVal) >= min
+	}
+	percent, err := strconv.Atoi(strings.TrimSuffix(s, "%"))
+	if !strings.HasSuffix(s, "%") || err != nil || percent < min || percent > 100 {
+		return nil, false
+	}
+	return &value, true
+}
+
+func (c *KubeVirtDeploymentConfig) GetStorageClassDefaults() map[string]string {
+	var data map[string]string
+	s, ok := c.AdditionalProperties[AdditionalPropertiesStorageClassDefaults]
+	if !ok {
+		return data
+	}
+	if err := json.Unmarshal([]byte(s), &data); err != nil {
+		fmt.Printf("Unable to parse storage class defaults: %v\n", err)
+		return nil
+	}
+	return data
+}
+
+func (c *KubeVirtDeploymentConfig) GetHandlerExtraVolumes() []k8sv1.Volume {
+	var data []k8sv1.Volume
+	s, ok := c.AdditionalProperties[AdditionalPropertiesHandlerExtraVolumes]
+	if !ok {
+		return data
+	}
+	if err := json.Unmarshal([]byte(s), &data); err != nil {
+		fmt.Printf("Unable to parse virt-handler extra volumes: %v\n", err)
+		return nil
+	}
+	return data
+}
+
+func (c *KubeVirtDeploymentConfig) GetHandlerExtraVolumeMounts() []k8sv1.VolumeMount {
+	var data []k8sv1.VolumeMount
+	s, ok := c.AdditionalProperties[AdditionalPropertiesHandlerExtraVolumeMounts]
+	if !ok {
+		return data
+	}
+	if err := json.Unmarshal([]byte(s), &data); err != nil {
+		fmt.Printf("Unable to parse virt-handler extra volume mounts: %v\n", err)
+		return nil
+	}
+	return data
+}
+
+// GetObjectPatches returns the JSON patches to apply to generated objects, keyed by "Kind/name"
+func (c *KubeVirtDeploymentConfig) GetObjectPatches() map[string]string {
+	var data map[string]string
+	s, ok := c.AdditionalProperties[AdditionalPropertiesObjectPatches]
+	if !ok {
+		return data
+	}
+	if err := json.Unmarshal([]byte(s), &data); err != nil {
+		fmt.Printf("Unable to parse object patches: %v\n", err)
+		return nil
+	}
+	return data
+}
+
 /*
 if the monitoring namespace field is defiend in kubevirtCR than return it
 otherwise we return common monitoring namespaces.
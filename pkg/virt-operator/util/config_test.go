@@ -22,12 +22,16 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	k8sv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/rand"
+
+	v1 "kubevirt.io/api/core/v1"
 )
 
 var _ = Describe("Operator Config", func() {
@@ -295,6 +299,142 @@ var _ = Describe("Operator Config", func() {
 
 	})
 
+	Context("resync interval", func() {
+		DescribeTable("should be parsed", func(value string, expected time.Duration, expectErr bool) {
+			config := &KubeVirtDeploymentConfig{AdditionalProperties: map[string]string{}}
+			if value != "" {
+				config.AdditionalProperties[AdditionalPropertiesResyncInterval] = value
+			}
+			interval, err := config.GetResyncInterval()
+			if expectErr {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(interval).To(Equal(expected))
+			}
+		},
+			Entry("as zero if not set", "", time.Duration(0), false),
+			Entry("from a duration", "15m", 15*time.Minute, false),
+			Entry("with an error if invalid", "often", time.Duration(0), true),
+		)
+	})
+
+	Context("resolving the config", func() {
+		It("should apply the defaults to a minimal KubeVirt CR", func() {
+			config, err := ResolveConfig(&v1.KubeVirt{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "kubevirt"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config.GetNamespace()).To(Equal("kubevirt"))
+			Expect(config.GetImagePullPolicy()).To(Equal(k8sv1.PullIfNotPresent))
+			Expect(config.GetVerbosity()).To(Equal("2"))
+			Expect(config.GetDeploymentID()).ToNot(BeEmpty())
+		})
+
+		It("should fail for an invalid KubeVirt CR", func() {
+			_, err := ResolveConfig(&v1.KubeVirt{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "kubevirt"},
+				Spec:       v1.KubeVirtSpec{ImagePullPolicy: "Sometimes"},
+			})
+			Expect(err).To(MatchError(ContainSubstring(`invalid image pull policy "Sometimes"`)))
+		})
+
+		It("should report every invalid additional property", func() {
+			config := &KubeVirtDeploymentConfig{AdditionalProperties: map[string]string{
+				AdditionalPropertiesResyncInterval:        "often",
+				AdditionalPropertiesHandlerMaxUnavailable: "0",
+				AdditionalPropertiesObjectPatches:         "[",
+			}}
+			err := config.Validate()
+			Expect(err).To(MatchError(ContainSubstring(`invalid resync interval "often"`)))
+			Expect(err).To(MatchError(ContainSubstring(`invalid virt-handler maxUnavailable "0"`)))
+			Expect(err).To(MatchError(ContainSubstring("invalid ObjectPatches")))
+		})
+	})
+
+	Context("additional properties from KubeVirt CR annotations", func() {
+		newKubeVirt := func(annotations map[string]string) *v1.KubeVirt {
+			return &v1.KubeVirt{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "kubevirt", Annotations: annotations},
+			}
+		}
+
+		It("should set the properties of the series from the KubeVirt CR", func() {
+			config, err := ResolveConfig(newKubeVirt(map[string]string{
+				DeploymentConfigAnnotationPrefix + AdditionalPropertiesArchitecture:       "arm64",
+				DeploymentConfigAnnotationPrefix + AdditionalPropertiesReconcileMode:      "Replace",
+				DeploymentConfigAnnotationPrefix + AdditionalPropertiesHandlerHostNetwork: "true",
+				DeploymentConfigAnnotationPrefix + AdditionalPropertiesPatchEvents:        "false",
+				DeploymentConfigAnnotationPrefix + AdditionalPropertiesReadinessGates:     `{"virt-api": ["example.org/ready"]}`,
+				"kubevirt.io/unrelated": "value",
+			}))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config.GetArchitecture()).To(Equal("arm64"))
+			Expect(config.GetReconcileMode()).To(Equal("Replace"))
+			Expect(config.HandlerHostNetworkEnabled()).To(BeTrue())
+			Expect(config.PatchEventsEnabled()).To(BeFalse())
+			Expect(config.GetReadinessGates()).To(Equal(map[string][]string{"virt-api": {"example.org/ready"}}))
+			Expect(config.AdditionalProperties).ToNot(HaveKey("unrelated"))
+		})
+
+		It("should change the deployment ID", func() {
+			plain := GetTargetConfigFromKV(newKubeVirt(nil))
+			configured := GetTargetConfigFromKV(newKubeVirt(map[string]string{
+				DeploymentConfigAnnotationPrefix + AdditionalPropertiesHandlerHostNetwork: "true",
+			}))
+			Expect(configured.GetDeploymentID()).ToNot(Equal(plain.GetDeploymentID()))
+		})
+
+		It("should ignore properties which can not be set by annotation", func() {
+			kv := newKubeVirt(map[string]string{
+				DeploymentConfigAnnotationPrefix + AdditionalPropertiesNamePullPolicy: string(k8sv1.PullNever),
+			})
+			kv.Spec.ImagePullPolicy = k8sv1.PullAlways
+			Expect(GetTargetConfigFromKV(kv).GetImagePullPolicy()).To(Equal(k8sv1.PullAlways))
+		})
+
+		It("should reject an invalid flag value", func() {
+			_, err := ResolveConfig(newKubeVirt(map[string]string{
+				DeploymentConfigAnnotationPrefix + AdditionalPropertiesHandlerHostNetwork: "yes",
+			}))
+			Expect(err).To(MatchError(`invalid value "yes" for HandlerHostNetwork, expected true or false`))
+		})
+	})
+
+	Context("latest image tag", func() {
+		newConfig := func(tag string, properties ...string) *KubeVirtDeploymentConfig {
+			additionalProperties := map[string]string{}
+			for _, property := range properties {
+				additionalProperties[property] = ""
+			}
+			return newDeploymentConfigWithTag("registry", "", tag, "kubevirt", "", "", "", "", "", "", "", "", "", additionalProperties, nil)
+		}
+
+		It("should be allowed in dev mode", func() {
+			Expect(newConfig("latest").Validate()).To(Succeed())
+
+			config, err := ResolveConfig(&v1.KubeVirt{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "kubevirt"},
+				Spec:       v1.KubeVirtSpec{ImageTag: "latest"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config.GetKubeVirtVersion()).To(Equal("latest"))
+		})
+
+		It("should be rejected in production mode", func() {
+			err := newConfig("latest", AdditionalPropertiesProductionMode).Validate()
+			Expect(err).To(MatchError(`image tag "latest" is not allowed in production mode`))
+		})
+
+		It("should be allowed in production mode with the override", func() {
+			Expect(newConfig("latest", AdditionalPropertiesProductionMode, AdditionalPropertiesAllowLatestTag).Validate()).To(Succeed())
+		})
+
+		It("should allow a release tag in production mode", func() {
+			Expect(newConfig("v1.0.0", AdditionalPropertiesProductionMode).Validate()).To(Succeed())
+		})
+	})
+
 	Context("Product Names and Versions", func() {
 		DescribeTable("label validation", func(testVector string, expectedResult bool) {
 			Expect(IsValidLabel(testVector)).To(Equal(expectedResult))
@@ -2367,7 +2367,7 @@ spec:
 
 				By("Checking if kubevirt SCCs have been created")
 				secClient := virtClient.SecClient()
-				operatorSCCs := components.GetAllSCC(flags.KubeVirtInstallNamespace)
+				operatorSCCs := components.GetAllSCC(flags.KubeVirtInstallNamespace, false)
 				for _, scc := range operatorSCCs {
 					expectedSCCs = append(expectedSCCs, scc.GetName())
 				}